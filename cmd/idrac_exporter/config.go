@@ -1,6 +1,7 @@
 package main
 
 import (
+	"strings"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -30,25 +31,74 @@ func ReloadConfig(filename string) {
 		return
 	}
 
+	old.Mutex.Lock()
+	restart := restartRequired(old, cfg)
+	hostsAdded, hostsRemoved, hostsChanged := config.DiffAuths(old.Hosts, cfg.Hosts)
+	authsAdded, authsRemoved, authsChanged := config.DiffAuths(old.Auths, cfg.Auths)
+	if old.Timeout != cfg.Timeout {
+		log.Info("Timeout changed from %d to %d seconds", old.Timeout, cfg.Timeout)
+	}
+	if old.Concurrency != cfg.Concurrency {
+		log.Info("Concurrency changed from %d to %d", old.Concurrency, cfg.Concurrency)
+	}
+	old.DefaultTarget = cfg.DefaultTarget
 	old.Collect = cfg.Collect
+	old.Refresh = cfg.Refresh
 	old.Event = cfg.Event
+	old.Retry = cfg.Retry
+	old.Breaker = cfg.Breaker
+	old.Secrets = cfg.Secrets
+	old.Collectors = cfg.Collectors
+	old.Timeout = cfg.Timeout
+	old.Concurrency = cfg.Concurrency
+	old.UseSelect = cfg.UseSelect
+	old.MaxPages = cfg.MaxPages
+	old.Hosts = cfg.Hosts
+	old.Auths = cfg.Auths
+	old.Mutex.Unlock()
+
+	logChanges("hosts", hostsAdded, hostsRemoved, hostsChanged)
+	logChanges("auths", authsAdded, authsRemoved, authsChanged)
+	if len(restart) > 0 {
+		log.Warn("Changes to %s require a restart of the exporter", strings.Join(restart, ", "))
+	}
 
-	old.Mutex.Lock()
-	defer old.Mutex.Unlock()
-
-	for k, v := range cfg.Hosts {
-		h, ok := old.Hosts[k]
-		if ok {
-			if h.Username != v.Username || h.Password != v.Password || h.Scheme != v.Scheme {
-				old.Hosts[k] = v
-				collector.Reset(k)
-			}
-		} else {
-			old.Hosts[k] = v
-		}
+	n := collector.ResetChanged()
+	log.Info("Configuration reload was successful, %d collectors were reset", n)
+}
+
+// Returns the settings that were changed, but cannot be applied without
+// restarting the exporter
+func restartRequired(old, cfg *config.RootConfig) []string {
+	var restart []string
+	if old.Address != cfg.Address || old.Port != cfg.Port {
+		restart = append(restart, "address")
 	}
+	if old.HttpsProxy != cfg.HttpsProxy {
+		restart = append(restart, "https_proxy")
+	}
+	if old.MetricsPrefix != cfg.MetricsPrefix {
+		restart = append(restart, "metrics_prefix")
+	}
+	if old.Poll.Enabled != cfg.Poll.Enabled || old.Poll.IntervalDuration != cfg.Poll.IntervalDuration {
+		restart = append(restart, "polling")
+	}
+	if old.TLS != cfg.TLS {
+		restart = append(restart, "tls")
+	}
+	return restart
+}
 
-	log.Info("Configuration reload was successful")
+func logChanges(section string, added, removed, changed []string) {
+	if len(added) > 0 {
+		log.Info("Added %s: %s", section, strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		log.Info("Removed %s: %s", section, strings.Join(removed, ", "))
+	}
+	if len(changed) > 0 {
+		log.Info("Changed %s: %s", section, strings.Join(changed, ", "))
+	}
 }
 
 func WatchConfig(filename string) {
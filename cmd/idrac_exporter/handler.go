@@ -2,11 +2,17 @@ package main
 
 import (
 	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/mrlhansen/idrac_exporter/internal/collector"
 	"github.com/mrlhansen/idrac_exporter/internal/config"
@@ -18,8 +24,12 @@ const (
 	contentTypeHeader     = "Content-Type"
 	contentEncodingHeader = "Content-Encoding"
 	acceptEncodingHeader  = "Accept-Encoding"
+	scrapeTimeoutHeader   = "X-Prometheus-Scrape-Timeout-Seconds"
 )
 
+// Time reserved for writing the response before the scrape timeout
+const scrapeTimeoutOffset = 500 * time.Millisecond
+
 var gzipPool = sync.Pool{
 	New: func() any {
 		return gzip.NewWriter(nil)
@@ -31,7 +41,22 @@ const landingPageTemplate = `<html lang="en">
 <body style="font-family: sans-serif">
 <h2>iDRAC Exporter</h2>
 <div>Build information: version=%s revision=%s</div>
-<ul><li><a href="/metrics">Metrics</a> (needs <code>target</code> parameter)</li></ul>
+<ul>
+<li><a href="/metrics">Metrics</a> (needs <code>target</code> parameter)</li>
+<li><a href="/exporter_metrics">Exporter Metrics</a></li>
+<li><a href="/status">Status</a></li>
+</ul>
+</body>
+</html>
+`
+
+const statusPageTemplate = `<html lang="en">
+<head><title>iDRAC Exporter Status</title></head>
+<body style="font-family: sans-serif">
+<h2>iDRAC Exporter Status</h2>
+<table cellpadding="4">
+<tr><th align="left">Target</th><th align="left">Circuit Breaker</th><th align="left">Failures</th><th align="left">Next Attempt</th></tr>
+%s</table>
 </body>
 </html>
 `
@@ -40,6 +65,21 @@ func rootHandler(rsp http.ResponseWriter, req *http.Request) {
 	fmt.Fprintf(rsp, landingPageTemplate, version.Version, version.Revision)
 }
 
+func statusHandler(rsp http.ResponseWriter, req *http.Request) {
+	var rows strings.Builder
+
+	for _, s := range collector.GetStatus() {
+		retry := ""
+		if s.State == collector.BreakerOpen {
+			retry = s.Retry.Format(time.RFC3339)
+		}
+		fmt.Fprintf(&rows, "<tr><td>%s</td><td>%s</td><td>%d</td><td>%s</td></tr>\n",
+			html.EscapeString(s.Target), s.State, s.Failures, retry)
+	}
+
+	fmt.Fprintf(rsp, statusPageTemplate, rows.String())
+}
+
 func healthHandler(rsp http.ResponseWriter, req *http.Request) {
 	// just return a simple 200 for now
 }
@@ -51,7 +91,7 @@ func reloadHandler(rsp http.ResponseWriter, req *http.Request) {
 func resetHandler(rsp http.ResponseWriter, req *http.Request) {
 	target := req.URL.Query().Get("target")
 	if target == "" {
-		target = config.Config.DefaultTarget
+		target = config.GetDefaultTarget()
 		if target == "" {
 			log.Error("Received request from %s without 'target' parameter", req.Host)
 			http.Error(rsp, "Query parameter 'target' is mandatory", http.StatusBadRequest)
@@ -68,10 +108,31 @@ func discoverHandler(rsp http.ResponseWriter, req *http.Request) {
 	fmt.Fprint(rsp, config.GetDiscover())
 }
 
+func matchHandler(rsp http.ResponseWriter, req *http.Request) {
+	target := req.URL.Query().Get("target")
+	if target == "" {
+		http.Error(rsp, "Query parameter 'target' is mandatory", http.StatusBadRequest)
+		return
+	}
+
+	b, err := json.Marshal(config.MatchHost(target))
+	if err != nil {
+		http.Error(rsp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rsp.Header().Set(contentTypeHeader, "application/json")
+	rsp.Write(b)
+}
+
+func exporterMetricsHandler(rsp http.ResponseWriter, req *http.Request) {
+	writeMetrics(rsp, req, collector.ExporterMetrics())
+}
+
 func metricsHandler(rsp http.ResponseWriter, req *http.Request) {
 	target := req.URL.Query().Get("target")
 	if target == "" {
-		target = config.Config.DefaultTarget
+		target = config.GetDefaultTarget()
 		if target == "" {
 			log.Error("Received request from %s without 'target' parameter", req.Host)
 			http.Error(rsp, "Query parameter 'target' is mandatory", http.StatusBadRequest)
@@ -79,29 +140,84 @@ func metricsHandler(rsp http.ResponseWriter, req *http.Request) {
 		}
 	}
 	auth := req.URL.Query().Get("auth")
+	groups := req.URL.Query().Get("collect")
 
 	log.Debug("Handling metrics request from %s for host %s", req.Host, target)
 
-	c, err := collector.GetCollector(target, auth)
+	collect, err := config.GetCollectConfig(target, auth, groups)
 	if err != nil {
-		errorMsg := fmt.Sprintf("Error instantiating metrics collector for host %s: %v", target, err)
-		log.Error("%v", errorMsg)
-		http.Error(rsp, errorMsg, http.StatusInternalServerError)
+		log.Error("Received request from %s with invalid 'collect' parameter: %v", req.Host, err)
+		http.Error(rsp, fmt.Sprintf("Invalid query parameter 'collect': %v", err), http.StatusBadRequest)
 		return
 	}
 
+	// Serve the result of the last background collection when available
+	if auth == "" && groups == "" && collector.IsPolled(target) {
+		metrics, ok := collector.GetSnapshot(target)
+		if ok {
+			writeMetrics(rsp, req, metrics)
+			return
+		}
+		log.Debug("No background collection completed for host %s yet", target)
+	}
+
+	ctx, cancel := scrapeContext(req)
+	defer cancel()
+
+	// Errors are reported using the up metric, such that the scrape succeeds
+	c, err := collector.GetCollector(ctx, target, auth)
+	if err != nil {
+		if errors.Is(err, collector.ErrBreakerOpen) {
+			log.Debug("Circuit breaker for host %s is open, skipping connection attempt", target)
+		} else {
+			log.Error("Error instantiating metrics collector for host %s: %v", target, err)
+		}
+		if c == nil {
+			writeMetrics(rsp, req, collector.Unavailable())
+			return
+		}
+	}
+
 	log.Debug("Collecting metrics for host %s", target)
 
-	metrics, err := c.Gather()
+	metrics, err := c.Gather(ctx, collect)
 	if err != nil {
-		errorMsg := fmt.Sprintf("Error collecting metrics for host %s: %v", target, err)
-		log.Error("%v", errorMsg)
-		http.Error(rsp, errorMsg, http.StatusInternalServerError)
+		log.Error("Error collecting metrics for host %s: %v", target, err)
+		writeMetrics(rsp, req, collector.Unavailable())
 		return
 	}
 
 	log.Debug("Metrics for host %s collected", target)
 
+	writeMetrics(rsp, req, metrics)
+}
+
+// scrapeContext returns the context for collecting metrics, which is cancelled
+// when the client disconnects or shortly before the scrape timeout of Prometheus
+func scrapeContext(req *http.Request) (context.Context, context.CancelFunc) {
+	ctx := req.Context()
+
+	s := req.Header.Get(scrapeTimeoutHeader)
+	if s == "" {
+		return context.WithCancel(ctx)
+	}
+
+	timeout, err := strconv.ParseFloat(s, 64)
+	if err != nil || timeout <= 0 {
+		log.Debug("Invalid value of header %s: %q", scrapeTimeoutHeader, s)
+		return context.WithCancel(ctx)
+	}
+
+	// Leave time for returning the partial results
+	d := time.Duration(timeout * float64(time.Second))
+	if d > 2*scrapeTimeoutOffset {
+		d -= scrapeTimeoutOffset
+	}
+
+	return context.WithTimeout(ctx, d)
+}
+
+func writeMetrics(rsp http.ResponseWriter, req *http.Request, metrics string) {
 	header := rsp.Header()
 	header.Set(contentTypeHeader, "text/plain")
 
@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/mrlhansen/idrac_exporter/internal/collector"
 	"github.com/mrlhansen/idrac_exporter/internal/config"
 	"github.com/mrlhansen/idrac_exporter/internal/log"
 	"github.com/mrlhansen/idrac_exporter/internal/version"
@@ -51,11 +52,20 @@ func main() {
 		log.SetLevel(log.LevelDebug)
 	}
 
+	if config.Config.Poll.Enabled {
+		collector.StartPolling()
+	}
+
+	collector.StartEviction()
+
 	http.HandleFunc("/discover", discoverHandler)
 	http.HandleFunc("/metrics", metricsHandler)
+	http.HandleFunc("/exporter_metrics", exporterMetricsHandler)
 	http.HandleFunc("/health", healthHandler)
 	http.HandleFunc("/reload", reloadHandler)
 	http.HandleFunc("/reset", resetHandler)
+	http.HandleFunc("/status", statusHandler)
+	http.HandleFunc("/match", matchHandler)
 	http.HandleFunc("/", rootHandler)
 
 	port := fmt.Sprintf("%d", config.Config.Port)
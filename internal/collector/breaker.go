@@ -0,0 +1,130 @@
+package collector
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mrlhansen/idrac_exporter/internal/config"
+	"github.com/mrlhansen/idrac_exporter/internal/log"
+)
+
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Breaker is a circuit breaker that stops connection attempts to a target
+// after a number of consecutive failures. After the cool-down period a single
+// attempt is allowed (half-open), which either closes or re-opens the breaker.
+type Breaker struct {
+	mu       sync.Mutex
+	state    BreakerState
+	failures uint
+	until    time.Time
+}
+
+// Allow reports whether a connection attempt should be made
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != BreakerOpen {
+		return true
+	}
+
+	if time.Now().Before(b.until) {
+		return false
+	}
+
+	b.state = BreakerHalfOpen
+	return true
+}
+
+// Success records a successful connection attempt and closes the breaker
+func (b *Breaker) Success(target string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != BreakerClosed {
+		log.Info("Circuit breaker for host %s closed", target)
+	}
+
+	b.state = BreakerClosed
+	b.failures = 0
+}
+
+// Failure records a failed connection attempt and opens the breaker when the
+// threshold is reached, or when the half-open attempt failed
+func (b *Breaker) Failure(target string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cfg := config.GetBreakerConfig()
+	b.failures++
+
+	if cfg.Threshold == 0 {
+		return
+	}
+
+	if b.state == BreakerHalfOpen || b.failures >= cfg.Threshold {
+		if b.state != BreakerHalfOpen {
+			log.Warn("Circuit breaker for host %s opened after %d consecutive failures", target, b.failures)
+		}
+		b.state = BreakerOpen
+		b.until = time.Now().Add(cfg.CooldownDuration)
+	}
+}
+
+// Status returns the current state, the number of consecutive failures and
+// the time of the next connection attempt when the breaker is open
+func (b *Breaker) Status() (BreakerState, uint, time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state, b.failures, b.until
+}
+
+type TargetStatus struct {
+	Target   string
+	State    BreakerState
+	Failures uint
+	Retry    time.Time
+}
+
+// GetStatus returns the circuit breaker status of all targets
+func GetStatus() []TargetStatus {
+	var list []TargetStatus
+
+	mu.Lock()
+	for target, c := range collectors {
+		state, failures, retry := c.breaker.Status()
+		list = append(list, TargetStatus{
+			Target:   target,
+			State:    state,
+			Failures: failures,
+			Retry:    retry,
+		})
+	}
+	mu.Unlock()
+
+	slices.SortFunc(list, func(a, b TargetStatus) int {
+		return strings.Compare(a.Target, b.Target)
+	})
+
+	return list
+}
@@ -1,6 +1,8 @@
 package collector
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"slices"
@@ -9,6 +11,7 @@ import (
 	"time"
 
 	"github.com/mrlhansen/idrac_exporter/internal/config"
+	"github.com/mrlhansen/idrac_exporter/internal/log"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -26,30 +29,124 @@ const (
 	HUAWEI
 )
 
+// Properties requested when the $select query parameter is in effect. These
+// must include every property used by the metrics for the given resource.
+var (
+	selectSystem = []string{
+		"PowerState", "Status", "IndicatorLED", "LocationIndicatorActive", "MemorySummary",
+		"ProcessorSummary", "BiosVersion", "Manufacturer", "Model", "SerialNumber", "SKU", "HostName",
+	}
+	selectThermal = []string{
+		"Temperatures", "Fans", "Redundancy",
+	}
+	selectPower = []string{
+		"PowerControl", "PowerSupplies", "Redundancy", "Oem",
+	}
+	selectRedundancy = []string{
+		"Redundancy",
+	}
+	selectVoltages = []string{
+		"Voltages",
+	}
+	selectSensor = []string{
+		"Id", "Name", "ReadingType", "ReadingUnits", "Reading", "PhysicalContext", "Status", "Thresholds",
+	}
+	selectManager = []string{
+		"Id", "ManagerType", "Model", "FirmwareVersion", "Status", "NetworkProtocol", "Links",
+	}
+	selectCertificate = []string{
+		"Id", "CertificateString", "SerialNumber", "Subject", "Issuer", "ValidNotAfter",
+	}
+	selectProcessor = []string{
+		"Id", "ProcessorType", "Status", "Socket", "Manufacturer", "Model", "InstructionSet",
+		"ProcessorArchitecture", "MaxSpeedMHz", "OperatingSpeedMHz", "TotalCores", "TotalThreads", "Oem",
+	}
+	selectMemory = []string{
+		"Id", "Name", "Status", "Manufacturer", "ErrorCorrection", "MemoryDeviceType",
+		"SerialNumber", "RankCount", "CapacityMiB", "OperatingSpeedMhz",
+		"Rank", "DIMMType", "DIMMStatus", "SizeMB", // iLO 4
+	}
+	selectNetworkAdapter = []string{
+		"Id", "Name", "Manufacturer", "Model", "SerialNumber", "Status", "Ports", "NetworkPorts",
+		"PhysicalPorts", // iLO 4
+	}
+	selectNetworkPort = []string{
+		"Id", "Status", "LinkStatus", "CurrentLinkSpeedMbps", "CurrentSpeedGbps", "MaxSpeedGbps",
+		"SupportedLinkCapabilities",
+	}
+	selectStorage = []string{
+		"Id", "Name", "Status", "Drives", "Controllers", "Volumes", "StorageControllers", "Oem",
+	}
+	selectDrive = []string{
+		"Id", "Name", "Status", "Manufacturer", "MediaType", "Model", "Protocol", "SerialNumber", "PhysicalLocation",
+		"CapacityBytes", "PredictedMediaLifeLeftPercent", "LocationIndicatorActive", "IndicatorLED", "Oem",
+		"CapacityMiB", "InterfaceType", "SSDEnduranceUtilizationPercentage", // iLO 4
+	}
+	selectController = []string{
+		"Id", "Name", "Manufacturer", "Model", "FirmwareVersion", "SpeedGbps", "Status", "CacheSummary",
+	}
+	selectVolume = []string{
+		"Id", "Name", "VolumeType", "RAIDType", "Status", "CapacityBytes", "MediaSpanCount", "Links",
+	}
+)
+
+// Paths of the resources that belong to a computer system
+type systemPath struct {
+	Id         string
+	System     string
+	Storage    string
+	Memory     string
+	Processors string
+}
+
+// Paths of the resources that belong to a chassis
+type chassisPath struct {
+	Id               string
+	Thermal          string
+	ThermalSubsystem string
+	Power            string
+	PowerSubsystem   string
+	Sensors          string
+	Network          string
+}
+
 type Client struct {
 	redfish *Redfish
+	event   config.EventConfig
 	vendor  int
 	version int
 	path    struct {
-		System           string
-		Thermal          string
-		ThermalSubsystem string
-		Power            string
-		PowerSubsystem   string
-		Storage          string
-		Memory           string
-		Network          string
-		Event            string
-		Processors       string
-		Manager          string
-		Extra            []string
-		RackPDUs         []string
-	}
-}
-
-func NewClient(host string, auth *config.AuthConfig) *Client {
+		Systems           []systemPath
+		Chassis           []chassisPath
+		ManagerCollection string
+		Managers          []string
+		Event             string
+		Certificates      string
+		Extra             []string
+		RackPDUs          []string
+	}
+	// Paths of the optional metrics groups are discovered when the group is
+	// collected for the first time. Collections of a host never overlap.
+	discovered struct {
+		managers bool
+		event    bool
+		extra    bool
+	}
+}
+
+func NewClient(ctx context.Context, host string, auth *config.AuthConfig) *Client {
+	r := NewRedfish(host, auth)
+	if r == nil {
+		return nil
+	}
+
+	// Connecting is cancelled together with the scrape
+	r.SetContext(ctx)
+	defer r.SetContext(context.Background())
+
 	client := &Client{
-		redfish: NewRedfish(host, auth),
+		redfish: r,
+		event:   auth.Event,
 	}
 
 	client.redfish.CreateSession()
@@ -65,8 +162,6 @@ func NewClient(host string, auth *config.AuthConfig) *Client {
 func (client *Client) findAllEndpoints() bool {
 	var root V1Response
 	var group GroupResponse
-	var chassis ChassisResponse
-	var system SystemResponse
 	var path string
 	var ok bool
 
@@ -76,6 +171,10 @@ func (client *Client) findAllEndpoints() bool {
 		return false
 	}
 
+	// Query parameters
+	client.redfish.SetExpand(&root)
+	client.redfish.SetSelect(&root)
+
 	// PDUs
 	if root.PowerDistribution != nil {
 		path = root.PowerDistribution.OdataId
@@ -91,7 +190,7 @@ func (client *Client) findAllEndpoints() bool {
 	}
 
 	if len(path) > 0 {
-		ok = client.redfish.Get(path, &group)
+		ok = client.redfish.GetGroup(path, &group)
 		if !ok {
 			return false
 		}
@@ -99,41 +198,47 @@ func (client *Client) findAllEndpoints() bool {
 		return true
 	}
 
-	// System
-	ok = client.redfish.Get(root.Systems.OdataId, &group)
+	// Systems (blade enclosures and multi-node servers have more than one)
+	systems, ok := GetMembers[SystemResponse](client.redfish, root.Systems.OdataId, nil)
 	if !ok {
 		return false
 	}
 
-	client.path.System = group.Members[0].OdataId
-
-	// Chassis
-	ok = client.redfish.Get(root.Chassis.OdataId, &group)
-	if !ok {
+	if len(systems) == 0 {
+		log.Error("No computer systems found on host %s", client.redfish.hostname)
 		return false
 	}
 
-	// Thermal and Power
-	ok = client.redfish.Get(group.Members[0].OdataId, &chassis)
-	if !ok {
-		return false
+	for _, c := range systems {
+		client.path.Systems = append(client.path.Systems, systemPath{
+			Id:         resourceId(c.Data.Id, c.Link),
+			System:     c.Link,
+			Storage:    c.Data.Storage.OdataId,
+			Memory:     c.Data.Memory.OdataId,
+			Processors: c.Data.Processors.OdataId,
+		})
 	}
 
-	ok = client.redfish.Get(client.path.System, &system)
+	// Chassis (thermal, power and network)
+	chassis, ok := GetMembers[ChassisResponse](client.redfish, root.Chassis.OdataId, nil)
 	if !ok {
 		return false
 	}
 
-	client.path.Storage = system.Storage.OdataId
-	client.path.Memory = system.Memory.OdataId
-	client.path.Network = chassis.NetworkAdapters.OdataId
-	client.path.Thermal = chassis.Thermal.OdataId
-	client.path.ThermalSubsystem = chassis.ThermalSubsystem.OdataId
-	client.path.Power = chassis.Power.OdataId
-	client.path.PowerSubsystem = chassis.PowerSubsystem.OdataId
-	client.path.Processors = system.Processors.OdataId
+	for _, c := range chassis {
+		client.path.Chassis = append(client.path.Chassis, chassisPath{
+			Id:               resourceId(c.Data.Id, c.Link),
+			Thermal:          c.Data.Thermal.OdataId,
+			ThermalSubsystem: c.Data.ThermalSubsystem.OdataId,
+			Power:            c.Data.Power.OdataId,
+			PowerSubsystem:   c.Data.PowerSubsystem.OdataId,
+			Sensors:          c.Data.Sensors.OdataId,
+			Network:          c.Data.NetworkAdapters.OdataId,
+		})
+	}
 
 	// Vendor
+	system := &systems[0].Data
 	m := strings.ToLower(system.Manufacturer)
 	if strings.Contains(m, "dell") {
 		client.vendor = DELL
@@ -166,97 +271,156 @@ func (client *Client) findAllEndpoints() bool {
 		client.vendor = DELL
 	}
 
-	// Path for manager
-	if config.Config.Collect.Manager {
-		ok = client.redfish.Get(root.Managers.OdataId, &group)
-		if ok && len(group.Members) > 0 {
-			client.path.Manager = group.Members[0].OdataId
+	// Managers, event log and extra resources are discovered on first use
+	client.path.ManagerCollection = root.Managers.OdataId
+	client.path.Certificates = root.CertificateService.OdataId
+
+	// Issue #50
+	if client.vendor == INSPUR {
+		for i := range client.path.Systems {
+			sys := &client.path.Systems[i]
+			sys.Storage = strings.ReplaceAll(sys.Storage, "Storages", "Storage")
 		}
 	}
 
-	// Path for event log
-	if config.Config.Collect.Events {
-		switch client.vendor {
-		case DELL:
-			{
-				pathA := "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries"
-				pathB := "/redfish/v1/Managers/iDRAC.Embedded.1/Logs/Sel"
-				if client.redfish.Exists(pathA) {
-					client.path.Event = pathA
-				} else if client.redfish.Exists(pathB) {
-					client.path.Event = pathB
-				}
-			}
-		case LENOVO:
-			{
-				pathA := "/redfish/v1/Systems/1/LogServices/PlatformLog/Entries"
-				pathB := "/redfish/v1/Systems/1/LogServices/StandardLog/Entries"
-				if client.redfish.Exists(pathA) {
-					client.path.Event = pathA
-				} else if client.redfish.Exists(pathB) {
-					client.path.Event = pathB
-				}
+	// Fix for iLO 4 machines, which only have a single system and chassis
+	if client.vendor == HPE {
+		if strings.Contains(root.Name, "HP RESTful") {
+			sys := &client.path.Systems[0]
+			sys.Memory = "/redfish/v1/Systems/1/Memory/"
+			sys.Storage = "/redfish/v1/Systems/1/SmartStorage/ArrayControllers/"
+			sys.Processors = "/redfish/v1/Systems/1/Processors/"
+			if len(client.path.Chassis) > 0 {
+				client.path.Chassis[0].Network = "/redfish/v1/Systems/1/NetworkAdapters/"
 			}
-		case HPE:
-			client.path.Event = "/redfish/v1/Systems/1/LogServices/IML/Entries"
-		case FUJITSU:
-			client.path.Event = "/redfish/v1/Managers/iRMC/LogServices/SystemEventLog/Entries"
-		case SUPERMICRO:
-			client.path.Event = "/redfish/v1/Systems/1/LogServices/Log1/Entries"
-		case ADVANTECH:
-			client.path.Event = "/redfish/v1/Systems/0/LogServices/Log/Entries"
+			client.version = 4
 		}
 	}
 
-	// Extra
-	if config.Config.Collect.Extra {
-		if client.vendor == DELL {
-			if client.redfish.Exists(DellSystemPath) {
-				client.path.Extra = append(client.path.Extra, DellSystemPath)
+	return true
+}
+
+// Discovers the managers of the host, which are used by the manager group
+func (client *Client) discoverManagers() bool {
+	if client.discovered.managers || client.path.ManagerCollection == "" {
+		return true
+	}
+
+	group := GroupResponse{}
+	ok := client.redfish.GetGroup(client.path.ManagerCollection, &group)
+	if !ok {
+		return false
+	}
+
+	client.path.Managers = group.Members.GetLinks()
+	client.discovered.managers = true
+	return true
+}
+
+// Discovers the event log of the host, which depends on the vendor
+func (client *Client) discoverEventLog() {
+	// Not supported for iLO 4
+	if client.discovered.event || client.version == 4 {
+		return
+	}
+
+	switch client.vendor {
+	case DELL:
+		{
+			pathA := "/redfish/v1/Managers/iDRAC.Embedded.1/LogServices/Sel/Entries"
+			pathB := "/redfish/v1/Managers/iDRAC.Embedded.1/Logs/Sel"
+			if client.redfish.Exists(pathA) {
+				client.path.Event = pathA
+			} else if client.redfish.Exists(pathB) {
+				client.path.Event = pathB
+			}
+		}
+	case LENOVO:
+		{
+			pathA := "/redfish/v1/Systems/1/LogServices/PlatformLog/Entries"
+			pathB := "/redfish/v1/Systems/1/LogServices/StandardLog/Entries"
+			if client.redfish.Exists(pathA) {
+				client.path.Event = pathA
+			} else if client.redfish.Exists(pathB) {
+				client.path.Event = pathB
 			}
 		}
+	case HPE:
+		client.path.Event = "/redfish/v1/Systems/1/LogServices/IML/Entries"
+	case FUJITSU:
+		client.path.Event = "/redfish/v1/Managers/iRMC/LogServices/SystemEventLog/Entries"
+	case SUPERMICRO:
+		client.path.Event = "/redfish/v1/Systems/1/LogServices/Log1/Entries"
+	case ADVANTECH:
+		client.path.Event = "/redfish/v1/Systems/0/LogServices/Log/Entries"
 	}
 
-	// Issue #50
-	if client.vendor == INSPUR {
-		client.path.Storage = strings.ReplaceAll(client.path.Storage, "Storages", "Storage")
+	// Probes that were cancelled together with the scrape are repeated
+	client.discovered.event = client.redfish.requestContext().Err() == nil
+}
+
+// Discovers the vendor specific resources used by the extra group
+func (client *Client) discoverExtra() {
+	if client.discovered.extra {
+		return
 	}
 
-	// Fix for iLO 4 machines
-	if client.vendor == HPE {
-		if strings.Contains(root.Name, "HP RESTful") {
-			client.path.Memory = "/redfish/v1/Systems/1/Memory/"
-			client.path.Storage = "/redfish/v1/Systems/1/SmartStorage/ArrayControllers/"
-			client.path.Network = "/redfish/v1/Systems/1/NetworkAdapters/"
-			client.path.Processors = "/redfish/v1/Systems/1/Processors/"
-			client.path.Event = ""
-			client.version = 4
+	if client.vendor == DELL {
+		if client.redfish.Exists(DellSystemPath) {
+			client.path.Extra = append(client.path.Extra, DellSystemPath)
 		}
 	}
 
-	return true
+	client.discovered.extra = client.redfish.requestContext().Err() == nil
 }
 
-func (client *Client) RefreshSensorsNew(mc *Collector, ch chan<- prometheus.Metric) bool {
+// Returns the identifier of a resource, which is the last element of the
+// link when the resource does not have an Id property
+func resourceId(id, link string) string {
+	if id != "" {
+		return id
+	}
+	s := strings.Split(strings.TrimSuffix(link, "/"), "/")
+	return s[len(s)-1]
+}
+
+// Calls refresh concurrently for every computer system, where the metrics
+// emitted by refresh are labeled with the identifier of the system
+func (client *Client) forEachSystem(ch chan<- prometheus.Metric, refresh func(ch chan<- prometheus.Metric, sys *systemPath) bool) bool {
+	return parallel(len(client.path.Systems), func(n int) bool {
+		sys := &client.path.Systems[n]
+		ch, done := withLabel(ch, "system_id", sys.Id)
+		defer done()
+		return refresh(ch, sys)
+	})
+}
+
+// Calls refresh concurrently for every chassis, where the metrics emitted by
+// refresh are labeled with the identifier of the chassis
+func (client *Client) forEachChassis(ch chan<- prometheus.Metric, refresh func(ch chan<- prometheus.Metric, chassis *chassisPath) bool) bool {
+	return parallel(len(client.path.Chassis), func(n int) bool {
+		chassis := &client.path.Chassis[n]
+		ch, done := withLabel(ch, "chassis_id", chassis.Id)
+		defer done()
+		return refresh(ch, chassis)
+	})
+}
+
+func (client *Client) RefreshSensorsNew(mc *Collector, ch chan<- prometheus.Metric, chassis *chassisPath) bool {
 	thermal := ThermalSubsystem{}
-	ok := client.redfish.Get(client.path.ThermalSubsystem, &thermal)
+	ok := client.redfish.Get(chassis.ThermalSubsystem, &thermal)
 	if !ok {
 		return false
 	}
 
 	if thermal.Fans.OdataId != "" {
-		group := GroupResponse{}
-		ok := client.redfish.Get(thermal.Fans.OdataId, &group)
+		fans, ok := GetMembers[ThermalFan](client.redfish, thermal.Fans.OdataId, nil)
 		if !ok {
 			return false
 		}
 
-		for _, c := range group.Members.GetLinks() {
-			fan := ThermalFan{}
-			ok = client.redfish.Get(c, &fan)
-			if !ok {
-				return false
-			}
+		for _, c := range fans {
+			fan := c.Data
 
 			units := "percent"
 			value := 0.0
@@ -270,8 +434,8 @@ func (client *Client) RefreshSensorsNew(mc *Collector, ch chan<- prometheus.Metr
 				continue
 			}
 
-			mc.NewSensorsFanHealth(ch, fan.Id, fan.Name, fan.Status.Health)
-			mc.NewSensorsFanSpeed(ch, value, fan.Id, fan.Name, strings.ToLower(units))
+			mc.NewSensorsFanHealth(ch, fan.Id, fan.Name, fan.PhysicalContext, fan.Status.Health)
+			mc.NewSensorsFanSpeed(ch, value, fan.Id, fan.Name, fan.PhysicalContext, strings.ToLower(units))
 		}
 
 	}
@@ -297,7 +461,7 @@ func (client *Client) RefreshSensorsNew(mc *Collector, ch chan<- prometheus.Metr
 					name = s[len(s)-1]
 				}
 
-				mc.NewSensorsTemperature(ch, *c.Reading, strconv.Itoa(n), name, "celsius")
+				mc.NewSensorsTemperature(ch, *c.Reading, strconv.Itoa(n), name, c.PhysicalContext, "celsius")
 			}
 		}
 	}
@@ -305,9 +469,9 @@ func (client *Client) RefreshSensorsNew(mc *Collector, ch chan<- prometheus.Metr
 	return true
 }
 
-func (client *Client) RefreshSensorsOld(mc *Collector, ch chan<- prometheus.Metric) bool {
+func (client *Client) RefreshSensorsOld(mc *Collector, ch chan<- prometheus.Metric, chassis *chassisPath) bool {
 	resp := ThermalResponse{}
-	ok := client.redfish.Get(client.path.Thermal, &resp)
+	ok := client.redfish.GetSelect(chassis.Thermal, selectThermal, &resp)
 	if !ok {
 		return false
 	}
@@ -322,7 +486,12 @@ func (client *Client) RefreshSensorsOld(mc *Collector, ch chan<- prometheus.Metr
 		}
 
 		id := t.GetId(n)
-		mc.NewSensorsTemperature(ch, t.ReadingCelsius, id, t.Name, "celsius")
+		mc.NewSensorsTemperature(ch, t.ReadingCelsius, id, t.Name, t.PhysicalContext, "celsius")
+		mc.NewSensorsTemperatureHealth(ch, id, t.Name, t.PhysicalContext, t.Status.Health)
+
+		for _, v := range t.Values() {
+			mc.NewSensorsTemperatureThreshold(ch, v.Value, id, t.Name, t.PhysicalContext, "celsius", v.Type)
+		}
 	}
 
 	for n, f := range resp.Fans {
@@ -341,26 +510,177 @@ func (client *Client) RefreshSensorsOld(mc *Collector, ch chan<- prometheus.Metr
 		}
 
 		id := f.GetId(n)
-		mc.NewSensorsFanHealth(ch, id, name, f.Status.Health)
-		mc.NewSensorsFanSpeed(ch, f.GetReading(), id, name, strings.ToLower(units))
+		units = strings.ToLower(units)
+		mc.NewSensorsFanHealth(ch, id, name, f.PhysicalContext, f.Status.Health)
+		mc.NewSensorsFanSpeed(ch, f.GetReading(), id, name, f.PhysicalContext, units)
+
+		for _, v := range f.Values() {
+			mc.NewSensorsFanSpeedThreshold(ch, v.Value, id, name, f.PhysicalContext, units, v.Type)
+		}
+	}
+
+	groups := resp.Redundancy
+	for _, f := range resp.Fans {
+		groups = append(groups, f.Redundancy...)
 	}
+	client.emitRedundancy(mc, ch, groups, "fan")
 
 	return true
 }
 
-func (client *Client) RefreshSensors(mc *Collector, ch chan<- prometheus.Metric) bool {
-	if client.path.Thermal != "" {
-		return client.RefreshSensorsOld(mc, ch)
+// RefreshSensorsCollection exports the readings from the Sensors collection of
+// the chassis, which supersedes the sensors in the Thermal and Power resources
+// Units of the reading types exported from the Sensors collection
+var sensorTypeUnits = map[string]string{
+	"Temperature": "celsius",
+	"Voltage":     "volts",
+	"Rotational":  "rpm",
+	"Power":       "watts",
+	"Current":     "amperes",
+	"Humidity":    "percent",
+	"AirFlow":     "cfm",
+}
+
+// Units of the readings in the labels, keyed by the UCUM units used by Redfish
+var ucumUnits = map[string]string{
+	"Cel":         "celsius",
+	"V":           "volts",
+	"RPM":         "rpm",
+	"%":           "percent",
+	"W":           "watts",
+	"A":           "amperes",
+	"[ft_i]3/min": "cfm",
+	"ft3/min":     "cfm",
+}
+
+// Returns the units of a sensor reading, which is false when the reading type
+// is not exported or the reading is given in other units than the metric
+func sensorUnits(readingType, readingUnits string) (string, bool) {
+	units, ok := sensorTypeUnits[readingType]
+	if !ok {
+		return "", false
+	}
+	if readingUnits == "" {
+		return units, true
+	}
+
+	// Fan speeds can also be given as a percentage of the maximum speed
+	u := ucumUnits[readingUnits]
+	if u == units || (readingType == "Rotational" && u == "percent") {
+		return u, true
+	}
+	return "", false
+}
+
+func (client *Client) RefreshSensorsCollection(mc *Collector, ch chan<- prometheus.Metric, chassis *chassisPath) bool {
+	sensors, ok := GetMembers[Sensor](client.redfish, chassis.Sensors, selectSensor)
+	if !ok {
+		return false
+	}
+
+	for _, c := range sensors {
+		s := c.Data
+
+		if s.Status.State != "" && s.Status.State != StateEnabled {
+			continue
+		}
+
+		id := resourceId(s.Id, c.Link)
+		name := s.Name
+		context := s.PhysicalContext
+
+		// The health is also reported for sensors without a usable reading
+		switch s.ReadingType {
+		case "Temperature":
+			mc.NewSensorsTemperatureHealth(ch, id, name, context, s.Status.Health)
+		case "Voltage":
+			mc.NewSensorsVoltageHealth(ch, id, name, context, s.Status.Health)
+		case "Rotational":
+			mc.NewSensorsFanHealth(ch, id, name, context, s.Status.Health)
+		}
+
+		if s.Reading == nil {
+			continue
+		}
+		value := *s.Reading
+
+		units, ok := sensorUnits(s.ReadingType, s.ReadingUnits)
+		if !ok {
+			log.Debug("Skipping sensor %s with reading type %q in units %q", c.Link, s.ReadingType, s.ReadingUnits)
+			continue
+		}
+
+		switch s.ReadingType {
+		case "Temperature":
+			mc.NewSensorsTemperature(ch, value, id, name, context, units)
+			for _, v := range s.Thresholds.Values() {
+				mc.NewSensorsTemperatureThreshold(ch, v.Value, id, name, context, units, v.Type)
+			}
+		case "Voltage":
+			mc.NewSensorsVoltage(ch, value, id, name, context, units)
+			for _, v := range s.Thresholds.Values() {
+				mc.NewSensorsVoltageThreshold(ch, v.Value, id, name, context, units, v.Type)
+			}
+		case "Rotational":
+			mc.NewSensorsFanSpeed(ch, value, id, name, context, units)
+			for _, v := range s.Thresholds.Values() {
+				mc.NewSensorsFanSpeedThreshold(ch, v.Value, id, name, context, units, v.Type)
+			}
+		case "Power":
+			mc.NewSensorsPower(ch, value, id, name, context, units)
+		case "Current":
+			mc.NewSensorsCurrent(ch, value, id, name, context, units)
+		case "Humidity":
+			mc.NewSensorsHumidity(ch, value, id, name, context, units)
+		case "AirFlow":
+			mc.NewSensorsAirflow(ch, value, id, name, context, units)
+		}
 	}
-	if client.path.ThermalSubsystem != "" {
-		return client.RefreshSensorsNew(mc, ch)
+
+	// The Sensors collection does not describe the fan redundancy, which is
+	// only found in the Thermal resource
+	if chassis.Thermal != "" {
+		resp := ThermalResponse{}
+		ok := client.redfish.GetSelect(chassis.Thermal, selectRedundancy, &resp)
+		if ok {
+			client.emitRedundancy(mc, ch, resp.Redundancy, "fan")
+		} else {
+			log.Debug("Skipping fan redundancy groups of %s", chassis.Thermal)
+		}
 	}
+
 	return true
 }
 
+func (client *Client) RefreshSensors(mc *Collector, ch chan<- prometheus.Metric) bool {
+	return client.forEachChassis(ch, func(ch chan<- prometheus.Metric, chassis *chassisPath) bool {
+		if chassis.Sensors != "" {
+			return client.RefreshSensorsCollection(mc, ch, chassis)
+		}
+		if chassis.Thermal != "" {
+			return client.RefreshSensorsOld(mc, ch, chassis)
+		}
+		if chassis.ThermalSubsystem != "" {
+			return client.RefreshSensorsNew(mc, ch, chassis)
+		}
+		return true
+	})
+}
+
 func (client *Client) RefreshSystem(mc *Collector, ch chan<- prometheus.Metric) bool {
+	return client.forEachSystem(ch, func(ch chan<- prometheus.Metric, sys *systemPath) bool {
+		return client.refreshSystem(mc, ch, sys)
+	})
+}
+
+func (client *Client) refreshSystem(mc *Collector, ch chan<- prometheus.Metric, sys *systemPath) bool {
+	props := selectSystem
+	if client.vendor == HPE {
+		props = append(slices.Clip(props), "Oem")
+	}
+
 	resp := SystemResponse{}
-	ok := client.redfish.Get(client.path.System, &resp)
+	ok := client.redfish.GetSelect(sys.System, props, &resp)
 	if !ok {
 		return false
 	}
@@ -383,44 +703,132 @@ func (client *Client) RefreshSystem(mc *Collector, ch chan<- prometheus.Metric)
 }
 
 func (client *Client) RefreshManager(mc *Collector, ch chan<- prometheus.Metric) bool {
-	mgr := ManagerResponse{}
-	ok := client.redfish.Get(client.path.Manager, &mgr)
+	if !client.discoverManagers() {
+		return false
+	}
+
+	managers, ok := GetResources[ManagerResponse](client.redfish, client.path.Managers, selectManager)
 	if !ok {
 		return false
 	}
 
-	// Issue #190 and #175
-	if client.vendor == DELL {
-		s := mgr.Links.Oem.Dell.DellAttributes.GetLinks()
-		if slices.Contains(s, DellAttributesPath) {
-			attr := DellAttributes{}
-			ok := client.redfish.Get(DellAttributesPath, &attr)
-			if ok {
-				mgr.Model = attr.Attributes.InfoHWModel
-				mgr.ManagerType = attr.Attributes.InfoType
+	for i := range managers {
+		mgr := &managers[i]
+
+		// Issue #190 and #175. The manager links to the attributes of several
+		// components, where its own attributes are named after the manager.
+		if client.vendor == DELL {
+			for _, link := range mgr.Links.Oem.Dell.DellAttributes.GetLinks() {
+				if !strings.HasSuffix(link, "/"+mgr.Id) {
+					continue
+				}
+				attr := DellAttributes{}
+				ok := client.redfish.Get(link, &attr)
+				if ok {
+					mgr.Model = attr.Attributes.InfoHWModel
+					mgr.ManagerType = attr.Attributes.InfoType
+				}
+				break
 			}
 		}
+
+		mc.NewManagerInfo(ch, mgr)
+		mc.NewManagerHealth(ch, mgr)
 	}
 
-	mc.NewManagerInfo(ch, &mgr)
-	mc.NewManagerHealth(ch, &mgr)
+	// Certificate used for the connection to the manager
+	state := client.redfish.TLSState()
+	if state != nil && len(state.PeerCertificates) > 0 {
+		mc.NewBmcTLSInfo(ch, state)
+		mc.NewBmcPeerCertificate(ch, state.PeerCertificates[0])
+	}
+
+	return client.RefreshCertificates(mc, ch, managers)
+}
+
+// RefreshCertificates exports the HTTPS certificates of the managers, which are
+// found via the network protocol settings or via the certificate service
+func (client *Client) RefreshCertificates(mc *Collector, ch chan<- prometheus.Metric, managers []ManagerResponse) bool {
+	var certs []Certificate
+
+	for _, mgr := range managers {
+		if mgr.NetworkProtocol.OdataId == "" {
+			continue
+		}
+
+		// Not every manager exposes its certificates, so the lookups are only
+		// done on a best-effort basis
+		proto := NetworkProtocolResponse{}
+		ok := client.redfish.Get(mgr.NetworkProtocol.OdataId, &proto)
+		if !ok || proto.HTTPS.Certificates.OdataId == "" {
+			continue
+		}
+
+		members, ok := GetMembers[Certificate](client.redfish, proto.HTTPS.Certificates.OdataId, selectCertificate)
+		if !ok {
+			log.Debug("Skipping certificates of manager %s on %s", mgr.Id, client.redfish.hostname)
+			continue
+		}
+		for _, m := range members {
+			certs = append(certs, m.Data)
+		}
+	}
+
+	if len(certs) == 0 && client.path.Certificates != "" {
+		certs = client.httpsCertificates()
+	}
+
+	for i := range certs {
+		mc.NewBmcCertificate(ch, &certs[i])
+	}
 
 	return true
 }
 
+// Returns the HTTPS certificates listed by the certificate service, which also
+// lists other certificates, e.g. those of user accounts or for secure boot
+func (client *Client) httpsCertificates() []Certificate {
+	service := CertificateServiceResponse{}
+	ok := client.redfish.Get(client.path.Certificates, &service)
+	if !ok || service.CertificateLocations.OdataId == "" {
+		return nil
+	}
+
+	locations := CertificateLocationsResponse{}
+	ok = client.redfish.Get(service.CertificateLocations.OdataId, &locations)
+	if !ok {
+		return nil
+	}
+
+	var links []string
+	for _, link := range locations.Links.Certificates.GetLinks() {
+		if strings.Contains(link, "/NetworkProtocol/HTTPS/") {
+			links = append(links, link)
+		}
+	}
+
+	certs, _ := GetResources[Certificate](client.redfish, links, selectCertificate)
+	return certs
+}
+
 func (client *Client) RefreshProcessors(mc *Collector, ch chan<- prometheus.Metric) bool {
-	group := GroupResponse{}
-	ok := client.redfish.Get(client.path.Processors, &group)
+	return client.forEachSystem(ch, func(ch chan<- prometheus.Metric, sys *systemPath) bool {
+		return client.refreshProcessors(mc, ch, sys)
+	})
+}
+
+func (client *Client) refreshProcessors(mc *Collector, ch chan<- prometheus.Metric, sys *systemPath) bool {
+	if sys.Processors == "" {
+		return true
+	}
+
+	processors, ok := GetMembers[Processor](client.redfish, sys.Processors, selectProcessor)
 	if !ok {
 		return false
 	}
 
-	for _, c := range group.Members.GetLinks() {
-		resp := Processor{}
-		ok = client.redfish.Get(c, &resp)
-		if !ok {
-			return false
-		}
+	for _, c := range processors {
+		resp := c.Data
 
 		if resp.ProcessorType != "CPU" {
 			continue
@@ -450,18 +858,36 @@ func (client *Client) RefreshProcessors(mc *Collector, ch chan<- prometheus.Metr
 }
 
 func (client *Client) RefreshNetwork(mc *Collector, ch chan<- prometheus.Metric) bool {
-	group := GroupResponse{}
-	ok := client.redfish.Get(client.path.Network, &group)
+	return client.forEachChassis(ch, func(ch chan<- prometheus.Metric, chassis *chassisPath) bool {
+		return client.refreshNetwork(mc, ch, chassis)
+	})
+}
+
+func (client *Client) refreshNetwork(mc *Collector, ch chan<- prometheus.Metric, chassis *chassisPath) bool {
+	if chassis.Network == "" {
+		return true
+	}
+
+	adapters, ok := GetMembers[NetworkAdapter](client.redfish, chassis.Network, selectNetworkAdapter)
 	if !ok {
 		return false
 	}
 
-	for _, c := range group.Members.GetLinks() {
-		ni := NetworkAdapter{}
-		ok = client.redfish.Get(c, &ni)
+	// The ports are fetched concurrently for all adapters
+	ports := make([][]Member[NetworkPort], len(adapters))
+	if (client.vendor != HPE) || (client.version != 4) {
+		ok = parallel(len(adapters), func(n int) bool {
+			list, ok := GetMembers[NetworkPort](client.redfish, adapters[n].Data.GetPorts(), selectNetworkPort)
+			ports[n] = list
+			return ok
+		})
 		if !ok {
 			return false
 		}
+	}
+
+	for n, c := range adapters {
+		ni := c.Data
 
 		// iLO 4
 		if (client.vendor == HPE) && (client.version == 4) {
@@ -482,23 +908,13 @@ func (client *Client) RefreshNetwork(mc *Collector, ch chan<- prometheus.Metric)
 		mc.NewNetworkAdapterInfo(ch, &ni)
 		mc.NewNetworkAdapterHealth(ch, &ni)
 
-		ports := GroupResponse{}
-		ok = client.redfish.Get(ni.GetPorts(), &ports)
-		if !ok {
-			return false
-		}
-
-		for _, c := range ports.Members.GetLinks() {
-			port := NetworkPort{}
-			ok = client.redfish.Get(c, &port)
-			if !ok {
-				return false
-			}
+		for _, c := range ports[n] {
+			port := c.Data
 
 			// Issue #92
 			if client.vendor == DELL {
 				if ni.Id == port.Id {
-					s := strings.Split(c, "/")
+					s := strings.Split(c.Link, "/")
 					port.Id = s[len(s)-1]
 				}
 			}
@@ -513,9 +929,9 @@ func (client *Client) RefreshNetwork(mc *Collector, ch chan<- prometheus.Metric)
 	return true
 }
 
-func (client *Client) RefreshPowerNew(mc *Collector, ch chan<- prometheus.Metric) bool {
+func (client *Client) RefreshPowerNew(mc *Collector, ch chan<- prometheus.Metric, chassis *chassisPath) bool {
 	power := PowerSubsystem{}
-	ok := client.redfish.Get(client.path.PowerSubsystem, &power)
+	ok := client.redfish.Get(chassis.PowerSubsystem, &power)
 	if !ok {
 		return false
 	}
@@ -526,28 +942,31 @@ func (client *Client) RefreshPowerNew(mc *Collector, ch chan<- prometheus.Metric
 		return true
 	}
 
-	group := GroupResponse{}
-	ok = client.redfish.Get(power.PowerSupplies.OdataId, &group)
+	supplies, ok := GetMembers[PowerSupply](client.redfish, power.PowerSupplies.OdataId, nil)
 	if !ok {
 		return false
 	}
 
-	for _, c := range group.Members.GetLinks() {
-		psu := PowerSupply{}
-		ok = client.redfish.Get(c, &psu)
-		if !ok {
-			return false
+	// The metrics are fetched concurrently for all power supplies
+	metrics := make([]PowerSupplyMetrics, len(supplies))
+	ok = parallel(len(supplies), func(n int) bool {
+		if c := supplies[n].Data.Metrics.OdataId; c != "" {
+			return client.redfish.Get(c, &metrics[n])
 		}
+		return true
+	})
+	if !ok {
+		return false
+	}
+
+	for n, c := range supplies {
+		psu := c.Data
 
 		mc.NewPowerSupplyHealth(ch, psu.Status.Health, psu.Id)
 		mc.NewPowerSupplyCapacityWatts(ch, psu.PowerCapacityWatts, psu.Id)
 
 		if psu.Metrics.OdataId != "" {
-			m := PowerSupplyMetrics{}
-			ok = client.redfish.Get(psu.Metrics.OdataId, &m)
-			if !ok {
-				return false
-			}
+			m := &metrics[n]
 
 			if m.InputVoltage != nil {
 				mc.NewPowerSupplyInputVoltage(ch, m.InputVoltage.Reading, psu.Id)
@@ -566,9 +985,9 @@ func (client *Client) RefreshPowerNew(mc *Collector, ch chan<- prometheus.Metric
 	return true
 }
 
-func (client *Client) RefreshPowerOld(mc *Collector, ch chan<- prometheus.Metric) bool {
+func (client *Client) RefreshPowerOld(mc *Collector, ch chan<- prometheus.Metric, chassis *chassisPath) bool {
 	resp := PowerResponse{}
-	ok := client.redfish.Get(client.path.Power, &resp)
+	ok := client.redfish.GetSelect(chassis.Power, selectPower, &resp)
 	if !ok {
 		return false
 	}
@@ -641,6 +1060,12 @@ func (client *Client) RefreshPowerOld(mc *Collector, ch chan<- prometheus.Metric
 		mc.NewPowerControlConsumedWatts(ch, resp.Oem.Public.TotalPower, "0", "Chassis Power")
 	}
 
+	groups := resp.Redundancy
+	for _, psu := range resp.PowerSupplies {
+		groups = append(groups, psu.Redundancy...)
+	}
+	client.emitRedundancy(mc, ch, groups, "power_supply")
+
 	for i, pc := range resp.PowerControl {
 		id := strconv.Itoa(i)
 		mc.NewPowerControlConsumedWatts(ch, pc.PowerConsumedWatts, id, pc.Name)
@@ -657,68 +1082,107 @@ func (client *Client) RefreshPowerOld(mc *Collector, ch chan<- prometheus.Metric
 		mc.NewPowerControlInterval(ch, pm.IntervalInMinutes, id, pc.Name)
 	}
 
-	// Voltage sensors belong to the sensors metrics group, but the data lives
-	// in the Power response fetched above, so when both groups are enabled they
-	// are emitted here at no additional cost.
-	if config.Config.Collect.Sensors {
-		client.emitVoltages(mc, ch, &resp)
-	}
-
 	return true
 }
 
-// RefreshVoltages emits the voltage sensors from the legacy Power resource. It
-// is used when the sensors metrics group is enabled but the power group is not;
-// when both groups are enabled the voltages are instead emitted during
-// RefreshPowerOld, from the response that is fetched there anyway.
+// RefreshVoltages emits the voltage sensors from the legacy Power resource. The
+// voltages are fetched separately from the power metrics group, since the
+// groups can have different refresh intervals. When the chassis has a Sensors
+// collection, the voltages are instead read from there.
 func (client *Client) RefreshVoltages(mc *Collector, ch chan<- prometheus.Metric) bool {
-	if client.path.Power == "" {
-		return true
-	}
+	return client.forEachChassis(ch, func(ch chan<- prometheus.Metric, chassis *chassisPath) bool {
+		if chassis.Power == "" || chassis.Sensors != "" {
+			return true
+		}
 
-	resp := PowerResponse{}
-	ok := client.redfish.Get(client.path.Power, &resp)
-	if !ok {
-		return false
-	}
+		resp := PowerResponse{}
+		ok := client.redfish.GetSelect(chassis.Power, selectVoltages, &resp)
+		if !ok {
+			return false
+		}
 
-	client.emitVoltages(mc, ch, &resp)
-	return true
+		client.emitVoltages(mc, ch, &resp)
+		return true
+	})
 }
 
 // emitVoltages emits the entries of the Voltages array found in the legacy
 // Power resource. On many BMCs (e.g. Lenovo XCC) this includes the board rails
-// and the CMOS/RTC battery. Entries without a usable reading are skipped.
+// and the CMOS/RTC battery. Entries without a usable reading only report their health.
 func (client *Client) emitVoltages(mc *Collector, ch chan<- prometheus.Metric, resp *PowerResponse) {
 	for i, v := range resp.Voltages {
 		if v.Name == "" {
 			continue
 		}
+
+		// The health is also reported for rails without a usable reading
+		id := strconv.Itoa(i)
+		mc.NewSensorsVoltageHealth(ch, id, v.Name, v.PhysicalContext, v.Status.Health)
+
 		value, ok := asFloat64(v.ReadingVolts)
 		if !ok {
 			continue
 		}
-		mc.NewSensorsVoltage(ch, value, strconv.Itoa(i), v.Name, "volts")
+
+		mc.NewSensorsVoltage(ch, value, id, v.Name, v.PhysicalContext, "volts")
+
+		for _, t := range v.Values() {
+			mc.NewSensorsVoltageThreshold(ch, t.Value, id, v.Name, v.PhysicalContext, "volts", t.Type)
+		}
 	}
 }
 
-func (client *Client) RefreshPower(mc *Collector, ch chan<- prometheus.Metric) bool {
-	if client.path.Power != "" {
-		return client.RefreshPowerOld(mc, ch)
-	}
-	if client.path.PowerSubsystem != "" {
-		return client.RefreshPowerNew(mc, ch)
+// emitRedundancy emits the redundancy groups of fans or power supplies, given
+// by kind. The groups are listed in the Thermal and Power resources, but they
+// are often also embedded in (or linked from) every member of the group, so
+// they are deduplicated by their member id or @odata.id. Links without any
+// details are skipped.
+func (client *Client) emitRedundancy(mc *Collector, ch chan<- prometheus.Metric, groups []Redundancy, kind string) {
+	seen := map[string]bool{}
+
+	for i, r := range groups {
+		if r.Name == "" && r.Status.Health == "" {
+			continue
+		}
+
+		id := r.MemberId
+		if id == "" {
+			id = r.OdataId
+		}
+		if id == "" {
+			id = strconv.Itoa(i)
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		mc.NewRedundancyHealth(ch, &r, id, kind)
+		mc.NewRedundancyMinNeeded(ch, &r, id, kind)
+		mc.NewRedundancyMaxSupported(ch, &r, id, kind)
 	}
-	return true
+}
+
+func (client *Client) RefreshPower(mc *Collector, ch chan<- prometheus.Metric) bool {
+	return client.forEachChassis(ch, func(ch chan<- prometheus.Metric, chassis *chassisPath) bool {
+		if chassis.Power != "" {
+			return client.RefreshPowerOld(mc, ch, chassis)
+		}
+		if chassis.PowerSubsystem != "" {
+			return client.RefreshPowerNew(mc, ch, chassis)
+		}
+		return true
+	})
 }
 
 func (client *Client) RefreshEventLog(mc *Collector, ch chan<- prometheus.Metric) bool {
+	client.discoverEventLog()
 	if client.path.Event == "" {
 		return true
 	}
 
-	resp := EventLogResponse{}
-	ok := client.redfish.Get(client.path.Event, &resp)
+	resp := CollectionResponse{}
+	ok := client.redfish.GetPages(client.path.Event, &resp)
 	if !ok {
 		return false
 	}
@@ -731,10 +1195,16 @@ func (client *Client) RefreshEventLog(mc *Collector, ch chan<- prometheus.Metric
 		}
 	}
 
-	level := config.Config.Event.SeverityLevel
-	maxage := config.Config.Event.MaxAgeSeconds
+	level := client.event.SeverityLevel
+	maxage := client.event.MaxAgeSeconds
+
+	for _, raw := range resp.Members {
+		e := EventLogEntry{}
+		err := json.Unmarshal(raw, &e)
+		if err != nil {
+			continue
+		}
 
-	for _, e := range resp.Members {
 		t, err := time.Parse(time.RFC3339, e.Created)
 		if err != nil {
 			continue
@@ -757,42 +1227,41 @@ func (client *Client) RefreshEventLog(mc *Collector, ch chan<- prometheus.Metric
 }
 
 func (client *Client) RefreshStorage(mc *Collector, ch chan<- prometheus.Metric) bool {
-	group := GroupResponse{}
-	ok := client.redfish.Get(client.path.Storage, &group)
+	return client.forEachSystem(ch, func(ch chan<- prometheus.Metric, sys *systemPath) bool {
+		return client.refreshStorage(mc, ch, sys)
+	})
+}
+
+func (client *Client) refreshStorage(mc *Collector, ch chan<- prometheus.Metric, sys *systemPath) bool {
+	if sys.Storage == "" {
+		return true
+	}
+
+	storages, ok := GetMembers[Storage](client.redfish, sys.Storage, selectStorage)
 	if !ok {
 		return false
 	}
 	re := regexp.MustCompile(`Chassis\/([^\/]+)`)
 
-	for _, c := range group.Members.GetLinks() {
-		storage := Storage{}
-		ok = client.redfish.Get(c, &storage)
-		if !ok {
-			return false
-		}
+	// The drives, controllers and volumes are fetched concurrently for all
+	// storage subsystems, and the metrics are emitted afterwards in order
+	details := make([]storageDetails, len(storages))
+	ok = parallel(len(storages), func(n int) bool {
+		return client.getStorageDetails(&storages[n], &details[n])
+	})
+	if !ok {
+		return false
+	}
 
-		// iLO 4
-		if (client.vendor == HPE) && (client.version == 4) {
-			grp := GroupResponse{}
-			ok = client.redfish.Get(c+"DiskDrives/", &grp)
-			if !ok {
-				return false
-			}
-			storage.Drives = grp.Members
-		}
+	for n, c := range storages {
+		storage := c.Data
 
 		mc.NewStorageInfo(ch, &storage)
 		mc.NewStorageHealth(ch, &storage)
 		mc.NewDellControllerBatteryHealth(ch, &storage)
 
 		// Drives
-		for _, c := range storage.Drives.GetLinks() {
-			drive := StorageDrive{}
-			ok = client.redfish.Get(c, &drive)
-			if !ok {
-				return false
-			}
-
+		for _, drive := range details[n].drives {
 			if drive.Status.State == StateAbsent {
 				continue
 			}
@@ -830,19 +1299,9 @@ func (client *Client) RefreshStorage(mc *Collector, ch chan<- prometheus.Metric)
 		}
 
 		// Controllers
-		if c := storage.Controllers.OdataId; len(c) > 0 {
-			grp := GroupResponse{}
-			ok = client.redfish.Get(c, &grp)
-			if !ok {
-				return false
-			}
-
-			for _, c := range grp.Members.GetLinks() {
-				ctlr := StorageController{}
-				ok = client.redfish.Get(c, &ctlr)
-				if !ok {
-					return false
-				}
+		if len(storage.Controllers.OdataId) > 0 {
+			for _, c := range details[n].controllers {
+				ctlr := c.Data
 
 				mc.NewStorageControllerInfo(ch, storage.Id, &ctlr)
 				mc.NewStorageControllerSpeed(ch, storage.Id, &ctlr)
@@ -871,44 +1330,81 @@ func (client *Client) RefreshStorage(mc *Collector, ch chan<- prometheus.Metric)
 		}
 
 		// Volumes
-		if c := storage.Volumes.OdataId; len(c) > 0 {
-			grp := GroupResponse{}
-			ok = client.redfish.Get(c, &grp)
-			if !ok {
-				return false
-			}
+		for _, c := range details[n].volumes {
+			vol := c.Data
 
-			for _, c := range grp.Members.GetLinks() {
-				vol := StorageVolume{}
-				ok = client.redfish.Get(c, &vol)
-				if !ok {
-					return false
-				}
-
-				mc.NewStorageVolumeInfo(ch, storage.Id, &vol)
-				mc.NewStorageVolumeHealth(ch, storage.Id, &vol)
-				mc.NewStorageVolumeCapacity(ch, storage.Id, &vol)
-				mc.NewStorageVolumeMediaSpan(ch, storage.Id, &vol)
-			}
+			mc.NewStorageVolumeInfo(ch, storage.Id, &vol)
+			mc.NewStorageVolumeHealth(ch, storage.Id, &vol)
+			mc.NewStorageVolumeCapacity(ch, storage.Id, &vol)
+			mc.NewStorageVolumeMediaSpan(ch, storage.Id, &vol)
 		}
 	}
 
 	return true
 }
 
+type storageDetails struct {
+	drives      []StorageDrive
+	controllers []Member[StorageController]
+	volumes     []Member[StorageVolume]
+}
+
+// getStorageDetails concurrently fetches the drives, controllers and volumes
+// that belong to the given storage subsystem
+func (client *Client) getStorageDetails(storage *Member[Storage], details *storageDetails) bool {
+	// iLO 4
+	if (client.vendor == HPE) && (client.version == 4) {
+		grp := GroupResponse{}
+		ok := client.redfish.GetGroup(storage.Link+"DiskDrives/", &grp)
+		if !ok {
+			return false
+		}
+		storage.Data.Drives = grp.Members
+		details.drives, ok = GetResources[StorageDrive](client.redfish, grp.Members.GetLinks(), nil)
+		return ok
+	}
+
+	props := selectDrive
+	if client.vendor == INSPUR {
+		props = append(slices.Clip(props), "Oem")
+	}
+
+	return parallel(3, func(n int) bool {
+		ok := true
+		switch n {
+		case 0:
+			details.drives, ok = GetResources[StorageDrive](client.redfish, storage.Data.Drives.GetLinks(), props)
+		case 1:
+			if c := storage.Data.Controllers.OdataId; len(c) > 0 {
+				details.controllers, ok = GetMembers[StorageController](client.redfish, c, selectController)
+			}
+		case 2:
+			if c := storage.Data.Volumes.OdataId; len(c) > 0 {
+				details.volumes, ok = GetMembers[StorageVolume](client.redfish, c, selectVolume)
+			}
+		}
+		return ok
+	})
+}
+
 func (client *Client) RefreshMemory(mc *Collector, ch chan<- prometheus.Metric) bool {
-	group := GroupResponse{}
-	ok := client.redfish.Get(client.path.Memory, &group)
+	return client.forEachSystem(ch, func(ch chan<- prometheus.Metric, sys *systemPath) bool {
+		return client.refreshMemory(mc, ch, sys)
+	})
+}
+
+func (client *Client) refreshMemory(mc *Collector, ch chan<- prometheus.Metric, sys *systemPath) bool {
+	if sys.Memory == "" {
+		return true
+	}
+
+	modules, ok := GetMembers[Memory](client.redfish, sys.Memory, selectMemory)
 	if !ok {
 		return false
 	}
 
-	for _, c := range group.Members.GetLinks() {
-		m := Memory{}
-		ok = client.redfish.Get(c, &m)
-		if !ok {
-			return false
-		}
+	for _, c := range modules {
+		m := c.Data
 
 		if (m.Status.State == StateAbsent) || (m.Id == "") {
 			continue
@@ -937,6 +1433,8 @@ func (client *Client) RefreshDell(mc *Collector, ch chan<- prometheus.Metric) bo
 		return true
 	}
 
+	client.discoverExtra()
+
 	result := true
 
 	if slices.Contains(client.path.Extra, DellSystemPath) {
@@ -1,33 +1,75 @@
 package collector
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/mrlhansen/idrac_exporter/internal/config"
+	"github.com/mrlhansen/idrac_exporter/internal/log"
 	"github.com/mrlhansen/idrac_exporter/internal/version"
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
 )
 
 var mu sync.Mutex
 var collectors = map[string]*Collector{}
 
+// ErrBreakerOpen is returned by GetCollector when no connection is attempted,
+// because the circuit breaker of the target is open
+var ErrBreakerOpen = errors.New("circuit breaker is open")
+
+// Metrics of a group that are reused until the refresh interval expires
+type cachedGroup struct {
+	metrics []prometheus.Metric
+	time    time.Time
+}
+
 type Collector struct {
 	// Internal variables
-	client     *Client
+	client     *Client // only changed with collected.L held while not collecting
 	registry   *prometheus.Registry
 	collected  *sync.Cond
 	collecting bool
+	running    atomic.Bool // same as collecting, but can be read without collected.L
+	collect    config.CollectConfig
 	errors     atomic.Uint64
 	builder    *strings.Builder
+	breaker    Breaker
+	status     *prometheus.Registry
+	auth       string             // auth group used by the client, protected by mu
+	settings   *config.AuthConfig // settings used by the client, protected by mu
+	used       time.Time          // time of the last use, protected by mu
+	snapshot   struct {
+		sync.Mutex
+		metrics string
+		time    time.Time
+	}
+	cache struct {
+		sync.Mutex
+		groups map[string]cachedGroup
+	}
 
 	// Exporter
+	Up                        *prometheus.Desc
 	ExporterBuildInfo         *prometheus.Desc
 	ExporterScrapeErrorsTotal *prometheus.Desc
+	ExporterSelectQuery       *prometheus.Desc
+	ExporterTruncatedTotal    *prometheus.Desc
+	ExporterCacheHitsTotal    *prometheus.Desc
+	ExporterCacheMissesTotal  *prometheus.Desc
+	ExporterRetriesTotal      *prometheus.Desc
+	ExporterBreakerState      *prometheus.Desc
+	CollectorSuccess          *prometheus.Desc
+	CollectorDuration         *prometheus.Desc
+	CollectorCacheAge         *prometheus.Desc
 
 	// System
 	SystemPowerOn         *prometheus.Desc
@@ -40,10 +82,24 @@ type Collector struct {
 	SystemMachineInfo     *prometheus.Desc
 
 	// Sensors
-	SensorsTemperature *prometheus.Desc
-	SensorsFanHealth   *prometheus.Desc
-	SensorsFanSpeed    *prometheus.Desc
-	SensorsVoltage     *prometheus.Desc
+	SensorsTemperature          *prometheus.Desc
+	SensorsFanHealth            *prometheus.Desc
+	SensorsFanSpeed             *prometheus.Desc
+	SensorsVoltage              *prometheus.Desc
+	SensorsTemperatureHealth    *prometheus.Desc
+	SensorsVoltageHealth        *prometheus.Desc
+	SensorsTemperatureThreshold *prometheus.Desc
+	SensorsFanSpeedThreshold    *prometheus.Desc
+	SensorsVoltageThreshold     *prometheus.Desc
+	SensorsPower                *prometheus.Desc
+	SensorsCurrent              *prometheus.Desc
+	SensorsHumidity             *prometheus.Desc
+	SensorsAirflow              *prometheus.Desc
+
+	// Redundancy
+	RedundancyHealth       *prometheus.Desc
+	RedundancyMinNeeded    *prometheus.Desc
+	RedundancyMaxSupported *prometheus.Desc
 
 	// Power supply
 	PowerSupplyHealth            *prometheus.Desc
@@ -108,6 +164,9 @@ type Collector struct {
 	// BMC
 	ManagerInfo   *prometheus.Desc
 	ManagerHealth *prometheus.Desc
+	BmcTLSInfo    *prometheus.Desc
+	BmcCertInfo   *prometheus.Desc
+	BmcCertExpiry *prometheus.Desc
 
 	// Dell OEM
 	DellBatteryRollupHealth       *prometheus.Desc
@@ -127,6 +186,11 @@ func NewCollector() *Collector {
 	prefix := config.Config.MetricsPrefix
 
 	collector := &Collector{
+		Up: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "", "up"),
+			"Whether the connection to the target could be established",
+			nil, nil,
+		),
 		ExporterBuildInfo: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "exporter", "build_info"),
 			"Constant metric with build information for the exporter",
@@ -141,6 +205,51 @@ func NewCollector() *Collector {
 			"Total number of errors encountered while scraping target",
 			nil, nil,
 		),
+		ExporterSelectQuery: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "exporter", "select_query_enabled"),
+			"Whether the Redfish $select query parameter is in effect for the target",
+			nil, nil,
+		),
+		ExporterTruncatedTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "exporter", "truncated_collections_total"),
+			"Total number of collections truncated because the page limit was reached",
+			nil, nil,
+		),
+		ExporterCacheHitsTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "exporter", "etag_cache_hits_total"),
+			"Total number of Redfish responses served from the ETag cache",
+			nil, nil,
+		),
+		ExporterCacheMissesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "exporter", "etag_cache_misses_total"),
+			"Total number of Redfish responses not served from the ETag cache",
+			nil, nil,
+		),
+		ExporterRetriesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "exporter", "retries_total"),
+			"Total number of Redfish requests retried due to transient errors",
+			nil, nil,
+		),
+		ExporterBreakerState: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "exporter", "circuit_breaker_state"),
+			"State of the circuit breaker for the target (0=closed, 1=open, 2=half-open)",
+			nil, nil,
+		),
+		CollectorSuccess: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "collector", "success"),
+			"Whether the collection of the metrics group was successful",
+			[]string{"group"}, nil,
+		),
+		CollectorDuration: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "collector", "duration_seconds"),
+			"Duration of the collection of the metrics group in seconds",
+			[]string{"group"}, nil,
+		),
+		CollectorCacheAge: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "collector", "cache_age_seconds"),
+			"Age of the cached metrics of the group in seconds, for groups with a refresh interval",
+			[]string{"group"}, nil,
+		),
 		SystemPowerOn: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "system", "power_on"),
 			"Power state of the system",
@@ -184,22 +293,82 @@ func NewCollector() *Collector {
 		SensorsTemperature: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "sensors", "temperature"),
 			"Sensors reporting temperature measurements",
-			[]string{"id", "name", "units"}, nil,
+			[]string{"id", "name", "physical_context", "units"}, nil,
 		),
 		SensorsFanHealth: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "sensors", "fan_health"),
 			"Health status for fans",
-			[]string{"id", "name", "status"}, nil,
+			[]string{"id", "name", "physical_context", "status"}, nil,
 		),
 		SensorsFanSpeed: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "sensors", "fan_speed"),
 			"Sensors reporting fan speed measurements",
-			[]string{"id", "name", "units"}, nil,
+			[]string{"id", "name", "physical_context", "units"}, nil,
 		),
 		SensorsVoltage: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "sensors", "voltage"),
 			"Sensors reporting voltage measurements",
-			[]string{"id", "name", "units"}, nil,
+			[]string{"id", "name", "physical_context", "units"}, nil,
+		),
+		SensorsTemperatureHealth: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "sensors", "temperature_health"),
+			"Health status for temperature sensors",
+			[]string{"id", "name", "physical_context", "status"}, nil,
+		),
+		SensorsVoltageHealth: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "sensors", "voltage_health"),
+			"Health status for voltage sensors",
+			[]string{"id", "name", "physical_context", "status"}, nil,
+		),
+		SensorsTemperatureThreshold: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "sensors", "temperature_threshold"),
+			"Thresholds of sensors reporting temperature measurements",
+			[]string{"id", "name", "physical_context", "units", "type"}, nil,
+		),
+		SensorsFanSpeedThreshold: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "sensors", "fan_speed_threshold"),
+			"Thresholds of sensors reporting fan speed measurements",
+			[]string{"id", "name", "physical_context", "units", "type"}, nil,
+		),
+		SensorsVoltageThreshold: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "sensors", "voltage_threshold"),
+			"Thresholds of sensors reporting voltage measurements",
+			[]string{"id", "name", "physical_context", "units", "type"}, nil,
+		),
+		SensorsPower: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "sensors", "power"),
+			"Sensors reporting power measurements",
+			[]string{"id", "name", "physical_context", "units"}, nil,
+		),
+		SensorsCurrent: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "sensors", "current"),
+			"Sensors reporting current measurements",
+			[]string{"id", "name", "physical_context", "units"}, nil,
+		),
+		SensorsHumidity: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "sensors", "humidity"),
+			"Sensors reporting humidity measurements",
+			[]string{"id", "name", "physical_context", "units"}, nil,
+		),
+		SensorsAirflow: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "sensors", "airflow"),
+			"Sensors reporting airflow measurements",
+			[]string{"id", "name", "physical_context", "units"}, nil,
+		),
+		RedundancyHealth: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "redundancy", "health"),
+			"Health status for redundancy groups of fans and power supplies",
+			[]string{"id", "name", "mode", "type", "status"}, nil,
+		),
+		RedundancyMinNeeded: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "redundancy", "min_needed"),
+			"Minimum number of members needed for the redundancy group to be redundant",
+			[]string{"id", "name", "mode", "type"}, nil,
+		),
+		RedundancyMaxSupported: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "redundancy", "max_supported"),
+			"Maximum number of members supported in the redundancy group",
+			[]string{"id", "name", "mode", "type"}, nil,
 		),
 		PowerSupplyHealth: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "power_supply", "health"),
@@ -441,6 +610,21 @@ func NewCollector() *Collector {
 			"Health status of the manager",
 			[]string{"id", "status"}, nil,
 		),
+		BmcTLSInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "bmc", "tls_info"),
+			"Information about the TLS connection to the manager",
+			[]string{"version", "cipher_suite", "server_name"}, nil,
+		),
+		BmcCertInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "bmc", "certificate_info"),
+			"Information about the certificates of the manager",
+			[]string{"source", "path", "subject", "issuer", "serial", "sans"}, nil,
+		),
+		BmcCertExpiry: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "bmc", "certificate_expiry_timestamp_seconds"),
+			"Unix timestamp of the expiry date of the certificates of the manager",
+			[]string{"source", "path", "subject", "issuer", "serial"}, nil,
+		),
 		DellBatteryRollupHealth: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "dell", "battery_rollup_health"),
 			"Health rollup status for the batteries",
@@ -490,15 +674,47 @@ func NewCollector() *Collector {
 
 	collector.builder = new(strings.Builder)
 	collector.collected = sync.NewCond(new(sync.Mutex))
+	collector.cache.groups = make(map[string]cachedGroup)
 	collector.registry = prometheus.NewRegistry()
 	collector.registry.Register(collector)
 
+	// Metrics that are added to the snapshot when it is served
+	collector.status = prometheus.NewRegistry()
+	collector.status.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: prometheus.BuildFQName(prefix, "exporter", "last_collection_timestamp_seconds"),
+			Help: "Unix timestamp of the last completed collection",
+		},
+		func() float64 {
+			return float64(collector.snapshotTime().Unix())
+		},
+	))
+	collector.status.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: prometheus.BuildFQName(prefix, "exporter", "collection_age_seconds"),
+			Help: "Number of seconds since the last completed collection",
+		},
+		func() float64 {
+			return time.Since(collector.snapshotTime()).Seconds()
+		},
+	))
+
 	return collector
 }
 
 func (collector *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.Up
 	ch <- collector.ExporterBuildInfo
 	ch <- collector.ExporterScrapeErrorsTotal
+	ch <- collector.ExporterSelectQuery
+	ch <- collector.ExporterTruncatedTotal
+	ch <- collector.ExporterCacheHitsTotal
+	ch <- collector.ExporterCacheMissesTotal
+	ch <- collector.ExporterRetriesTotal
+	ch <- collector.ExporterBreakerState
+	ch <- collector.CollectorSuccess
+	ch <- collector.CollectorDuration
+	ch <- collector.CollectorCacheAge
 	ch <- collector.SystemPowerOn
 	ch <- collector.SystemHealth
 	ch <- collector.SystemIndicatorLED
@@ -511,6 +727,18 @@ func (collector *Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- collector.SensorsFanHealth
 	ch <- collector.SensorsFanSpeed
 	ch <- collector.SensorsVoltage
+	ch <- collector.SensorsTemperatureHealth
+	ch <- collector.SensorsVoltageHealth
+	ch <- collector.SensorsTemperatureThreshold
+	ch <- collector.SensorsFanSpeedThreshold
+	ch <- collector.SensorsVoltageThreshold
+	ch <- collector.SensorsPower
+	ch <- collector.SensorsCurrent
+	ch <- collector.SensorsHumidity
+	ch <- collector.SensorsAirflow
+	ch <- collector.RedundancyHealth
+	ch <- collector.RedundancyMinNeeded
+	ch <- collector.RedundancyMaxSupported
 	ch <- collector.PowerSupplyHealth
 	ch <- collector.PowerSupplyOutputWatts
 	ch <- collector.PowerSupplyInputWatts
@@ -559,6 +787,9 @@ func (collector *Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- collector.CpuTotalThreads
 	ch <- collector.ManagerInfo
 	ch <- collector.ManagerHealth
+	ch <- collector.BmcTLSInfo
+	ch <- collector.BmcCertInfo
+	ch <- collector.BmcCertExpiry
 	ch <- collector.DellBatteryRollupHealth
 	ch <- collector.DellEstimatedSystemAirflowCFM
 	ch <- collector.DellControllerBatteryHealth
@@ -570,161 +801,249 @@ func (collector *Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- collector.PduEnergyKWh
 }
 
-func (collector *Collector) CollectServer(ch chan<- prometheus.Metric) {
-	var wg sync.WaitGroup
-	collect := &config.Config.Collect
+// Collects a single group of metrics and reports whether the collection was
+// successful and how long it took
+func (collector *Collector) collectGroup(name string, ch chan<- prometheus.Metric, refresh func(ch chan<- prometheus.Metric) bool) bool {
+	start := time.Now()
 
-	if collect.System {
-		wg.Add(1)
-		go func() {
-			ok := collector.client.RefreshSystem(collector, ch)
-			if !ok {
-				collector.errors.Add(1)
-			}
-			wg.Done()
-		}()
+	ok := collector.refreshGroup(name, ch, refresh)
+	if !ok {
+		collector.errors.Add(1)
 	}
 
-	if collect.Sensors {
-		wg.Add(1)
-		go func() {
-			ok := collector.client.RefreshSensors(collector, ch)
-			if !ok {
-				collector.errors.Add(1)
-			}
-			// Voltage sensors live in the legacy Power resource. When the power
-			// group is enabled they are emitted as part of that collection (at
-			// no extra cost), otherwise they are fetched here.
-			if !collect.Power {
-				ok = collector.client.RefreshVoltages(collector, ch)
-				if !ok {
-					collector.errors.Add(1)
-				}
-			}
-			wg.Done()
-		}()
-	}
+	collector.NewCollectorSuccess(ch, name, ok)
+	collector.NewCollectorDuration(ch, name, time.Since(start))
 
-	if collect.Power {
-		wg.Add(1)
-		go func() {
-			ok := collector.client.RefreshPower(collector, ch)
-			if !ok {
-				collector.errors.Add(1)
-			}
-			wg.Done()
-		}()
+	return ok
+}
+
+// Refreshes a single group of metrics. When a refresh interval is configured
+// for the group, the metrics are cached and reused until the interval expires,
+// and the age of the cached metrics is reported.
+func (collector *Collector) refreshGroup(name string, ch chan<- prometheus.Metric, refresh func(ch chan<- prometheus.Metric) bool) bool {
+	ttl := config.GetRefreshInterval(name)
+	if ttl == 0 {
+		return refresh(ch)
 	}
 
-	if collect.Network {
-		wg.Add(1)
-		go func() {
-			ok := collector.client.RefreshNetwork(collector, ch)
-			if !ok {
-				collector.errors.Add(1)
-			}
-			wg.Done()
-		}()
+	collector.cache.Lock()
+	group, ok := collector.cache.groups[name]
+	collector.cache.Unlock()
+
+	if ok && time.Since(group.time) < ttl {
+		for _, m := range group.metrics {
+			ch <- m
+		}
+		collector.NewCollectorCacheAge(ch, name, time.Since(group.time))
+		return true
 	}
 
-	if collect.Events {
-		wg.Add(1)
-		go func() {
-			ok := collector.client.RefreshEventLog(collector, ch)
-			if !ok {
-				collector.errors.Add(1)
-			}
-			wg.Done()
-		}()
+	var metrics []prometheus.Metric
+	tmp := make(chan prometheus.Metric)
+	done := make(chan struct{})
+
+	go func() {
+		for m := range tmp {
+			metrics = append(metrics, m)
+			ch <- m
+		}
+		close(done)
+	}()
+
+	ok = refresh(tmp)
+	close(tmp)
+	<-done
+
+	if !ok {
+		return false
 	}
 
-	if collect.Storage {
-		wg.Add(1)
-		go func() {
-			ok := collector.client.RefreshStorage(collector, ch)
-			if !ok {
-				collector.errors.Add(1)
-			}
-			wg.Done()
-		}()
+	collector.cache.Lock()
+	collector.cache.groups[name] = cachedGroup{
+		metrics: metrics,
+		time:    time.Now(),
 	}
+	collector.cache.Unlock()
 
-	if collect.Memory {
-		wg.Add(1)
-		go func() {
-			ok := collector.client.RefreshMemory(collector, ch)
-			if !ok {
-				collector.errors.Add(1)
-			}
-			wg.Done()
-		}()
+	collector.NewCollectorCacheAge(ch, name, 0)
+	return true
+}
+
+// A metric with an additional label, which identifies the system or chassis
+// that the metric belongs to
+type labeledMetric struct {
+	prometheus.Metric
+	label *dto.LabelPair
+}
+
+func (m *labeledMetric) Write(out *dto.Metric) error {
+	err := m.Metric.Write(out)
+	if err != nil {
+		return err
 	}
 
-	if collect.Processors {
-		wg.Add(1)
-		go func() {
-			ok := collector.client.RefreshProcessors(collector, ch)
-			if !ok {
-				collector.errors.Add(1)
-			}
-			wg.Done()
-		}()
+	// The labels of the inner metric may be shared, so they are copied
+	out.Label = append(slices.Clone(out.Label), m.label)
+	slices.SortFunc(out.Label, func(a, b *dto.LabelPair) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+
+	return nil
+}
+
+// Returns a channel that adds the given label to all metrics and forwards them
+// to ch. The returned function must be called when no more metrics are sent.
+func withLabel(ch chan<- prometheus.Metric, name, value string) (chan<- prometheus.Metric, func()) {
+	tmp := make(chan prometheus.Metric)
+	done := make(chan struct{})
+	label := &dto.LabelPair{Name: &name, Value: &value}
+
+	go func() {
+		for m := range tmp {
+			ch <- &labeledMetric{Metric: m, label: label}
+		}
+		close(done)
+	}()
+
+	return tmp, func() {
+		close(tmp)
+		<-done
 	}
+}
 
-	if collect.Manager {
-		wg.Add(1)
-		go func() {
-			ok := collector.client.RefreshManager(collector, ch)
-			if !ok {
-				collector.errors.Add(1)
-			}
-			wg.Done()
-		}()
+// Collects all enabled metrics groups of the server. Returns false when every
+// group failed, which means that the target is not responding.
+func (collector *Collector) CollectServer(ch chan<- prometheus.Metric) bool {
+	var wg sync.WaitGroup
+	var failed atomic.Int32
+	client := collector.client
+	collect := &collector.collect
+
+	groups := []struct {
+		name    string
+		enabled bool
+		refresh func(ch chan<- prometheus.Metric) bool
+	}{
+		{"system", collect.System, func(ch chan<- prometheus.Metric) bool {
+			return client.RefreshSystem(collector, ch)
+		}},
+		{"sensors", collect.Sensors, func(ch chan<- prometheus.Metric) bool {
+			ok := client.RefreshSensors(collector, ch)
+			return client.RefreshVoltages(collector, ch) && ok
+		}},
+		{"power", collect.Power, func(ch chan<- prometheus.Metric) bool {
+			return client.RefreshPower(collector, ch)
+		}},
+		{"network", collect.Network, func(ch chan<- prometheus.Metric) bool {
+			return client.RefreshNetwork(collector, ch)
+		}},
+		{"events", collect.Events, func(ch chan<- prometheus.Metric) bool {
+			return client.RefreshEventLog(collector, ch)
+		}},
+		{"storage", collect.Storage, func(ch chan<- prometheus.Metric) bool {
+			return client.RefreshStorage(collector, ch)
+		}},
+		{"memory", collect.Memory, func(ch chan<- prometheus.Metric) bool {
+			return client.RefreshMemory(collector, ch)
+		}},
+		{"processors", collect.Processors, func(ch chan<- prometheus.Metric) bool {
+			return client.RefreshProcessors(collector, ch)
+		}},
+		{"manager", collect.Manager, func(ch chan<- prometheus.Metric) bool {
+			return client.RefreshManager(collector, ch)
+		}},
+		{"extra", collect.Extra, func(ch chan<- prometheus.Metric) bool {
+			return client.RefreshDell(collector, ch)
+		}},
 	}
 
-	if collect.Extra {
+	enabled := 0
+	for _, g := range groups {
+		if !g.enabled {
+			continue
+		}
+		enabled++
 		wg.Add(1)
 		go func() {
-			ok := collector.client.RefreshDell(collector, ch)
-			if !ok {
-				collector.errors.Add(1)
+			if !collector.collectGroup(g.name, ch, g.refresh) {
+				failed.Add(1)
 			}
 			wg.Done()
 		}()
 	}
 
 	wg.Wait()
+
+	return enabled == 0 || int(failed.Load()) < enabled
 }
 
 func (collector *Collector) Collect(ch chan<- prometheus.Metric) {
+	state, _, _ := collector.breaker.Status()
+	collector.NewExporterBreakerState(ch, state)
+	ch <- prometheus.MustNewConstMetric(collector.ExporterBuildInfo, prometheus.UntypedValue, 1)
+
+	// The circuit breaker is open or the connection failed
+	if collector.client == nil {
+		collector.NewUp(ch, false)
+		return
+	}
+
+	// The circuit breaker was opened by previous scrapes that failed
+	if !collector.breaker.Allow() {
+		collector.NewUp(ch, false)
+		return
+	}
+
+	collector.NewUp(ch, true)
 	collector.client.redfish.RefreshSession()
 
+	var ok bool
 	if len(collector.client.path.RackPDUs) > 0 {
-		ok := collector.client.RefreshPDUs(collector, ch)
-		if !ok {
-			collector.errors.Add(1)
-		}
+		ok = collector.collectGroup("pdu", ch, func(ch chan<- prometheus.Metric) bool {
+			return collector.client.RefreshPDUs(collector, ch)
+		})
 	} else {
-		collector.CollectServer(ch)
+		ok = collector.CollectServer(ch)
+	}
+
+	if ok {
+		collector.breaker.Success(collector.client.redfish.hostname)
+	} else {
+		collector.breaker.Failure(collector.client.redfish.hostname)
 	}
 
-	ch <- prometheus.MustNewConstMetric(collector.ExporterBuildInfo, prometheus.UntypedValue, 1)
 	ch <- prometheus.MustNewConstMetric(collector.ExporterScrapeErrorsTotal, prometheus.CounterValue, float64(collector.errors.Load()))
+	collector.NewExporterSelectQuery(ch, collector.client.redfish.SelectEnabled())
+	collector.NewExporterTruncatedTotal(ch, collector.client.redfish.Truncated())
+
+	hits, misses := collector.client.redfish.CacheStats()
+	collector.NewExporterCacheStats(ch, hits, misses)
+	collector.NewExporterRetriesTotal(ch, collector.client.redfish.Retries())
 }
 
-func (collector *Collector) Gather() (string, error) {
+// Gather collects the given metrics groups from the target. Outstanding
+// requests are cancelled when the context is done, in which case partial
+// results are returned.
+func (collector *Collector) Gather(ctx context.Context, collect config.CollectConfig) (string, error) {
 	collector.collected.L.Lock()
 
-	// If a collection is already in progress wait for it to complete and return the cached data
-	if collector.collecting {
+	// If a collection of the same metrics groups is already in progress wait for it to
+	// complete and return the cached data, otherwise wait for it to complete and start over
+	for collector.collecting {
+		same := collector.collect == collect
 		collector.collected.Wait()
-		metrics := collector.builder.String()
-		collector.collected.L.Unlock()
-		return metrics, nil
+		if same {
+			metrics := collector.builder.String()
+			collector.collected.L.Unlock()
+			return metrics, nil
+		}
 	}
 
 	// Set collecting to true and let other goroutines enter in critical section
 	collector.collecting = true
+	collector.running.Store(true)
+	collector.collect = collect
+	client := collector.client
 	collector.collected.L.Unlock()
 
 	// Defer set collecting to false and wake waiting goroutines
@@ -732,12 +1051,18 @@ func (collector *Collector) Gather() (string, error) {
 		collector.collected.L.Lock()
 		collector.collected.Broadcast()
 		collector.collecting = false
+		collector.running.Store(false)
 		collector.collected.L.Unlock()
 	}()
 
 	// Collect metrics
 	collector.builder.Reset()
 
+	if client != nil {
+		client.redfish.SetContext(ctx)
+		defer client.redfish.SetContext(context.Background())
+	}
+
 	m, err := collector.registry.Gather()
 	if err != nil {
 		return "", err
@@ -750,40 +1075,193 @@ func (collector *Collector) Gather() (string, error) {
 	return collector.builder.String(), nil
 }
 
+// setSnapshot stores the metrics of a background collection, which are served
+// by Snapshot until the next one completes
+func (collector *Collector) setSnapshot(metrics string) {
+	collector.snapshot.Lock()
+	collector.snapshot.metrics = metrics
+	collector.snapshot.time = time.Now()
+	collector.snapshot.Unlock()
+}
+
+// Snapshot returns the metrics from the last background collection, together
+// with metrics describing when the collection took place. The second return
+// value is false when no collection has been completed yet.
+func (collector *Collector) Snapshot() (string, bool) {
+	collector.snapshot.Lock()
+	metrics := collector.snapshot.metrics
+	t := collector.snapshot.time
+	collector.snapshot.Unlock()
+
+	if t.IsZero() {
+		return "", false
+	}
+
+	m, err := collector.status.Gather()
+	if err != nil {
+		return metrics, true
+	}
+
+	builder := new(strings.Builder)
+	builder.WriteString(metrics)
+	for i := range m {
+		expfmt.MetricFamilyToText(builder, m[i])
+	}
+
+	return builder.String(), true
+}
+
+func (collector *Collector) snapshotTime() time.Time {
+	collector.snapshot.Lock()
+	defer collector.snapshot.Unlock()
+	return collector.snapshot.time
+}
+
+// Close waits for a running collection to complete and deletes the session
+// with the target. The collector reports the target as down afterwards.
+func (collector *Collector) Close() {
+	collector.collected.L.Lock()
+	for collector.collecting {
+		collector.collected.Wait()
+	}
+	client := collector.client
+	collector.client = nil
+	collector.collected.L.Unlock()
+
+	if client != nil {
+		client.redfish.DeleteSession()
+	}
+}
+
+// Unavailable returns the metrics served when the target could not be scraped
+// at all, which only report the target as being down
+func Unavailable() string {
+	up := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: prometheus.BuildFQName(config.Config.MetricsPrefix, "", "up"),
+		Help: "Whether the connection to the target could be established",
+	})
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(up)
+
+	m, err := registry.Gather()
+	if err != nil {
+		return ""
+	}
+
+	builder := new(strings.Builder)
+	for i := range m {
+		expfmt.MetricFamilyToText(builder, m[i])
+	}
+
+	return builder.String()
+}
+
+// ExporterMetrics returns the metrics describing the exporter as a whole,
+// rather than a single target
+func ExporterMetrics() string {
+	count := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: prometheus.BuildFQName(config.Config.MetricsPrefix, "exporter", "collectors"),
+		Help: "Number of targets with a live metrics collector in the exporter",
+	}, func() float64 {
+		return float64(Count())
+	})
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(count)
+
+	m, err := registry.Gather()
+	if err != nil {
+		return ""
+	}
+
+	builder := new(strings.Builder)
+	for i := range m {
+		expfmt.MetricFamilyToText(builder, m[i])
+	}
+
+	return builder.String()
+}
+
 // Resets an existing collector of the given target
 func Reset(target string) {
 	mu.Lock()
 	_, ok := collectors[target]
 	if ok {
-		delete(collectors, target)
+		evict(target)
 	}
 	mu.Unlock()
 }
 
-func GetCollector(target, auth string) (*Collector, error) {
+// Resets the collectors of all targets whose settings were changed or removed,
+// which is used after the configuration was reloaded. Returns the number of
+// collectors that were reset.
+func ResetChanged() int {
+	mu.Lock()
+	defer mu.Unlock()
+
+	n := 0
+	for target, c := range collectors {
+		if c.settings == nil {
+			continue
+		}
+		settings := config.LookupAuthConfig(target, c.auth)
+		if settings != nil && settings.Equal(c.settings) {
+			continue
+		}
+		log.Debug("Settings of host %s changed, resetting collector", target)
+		evict(target)
+		n++
+	}
+
+	return n
+}
+
+// Returns the collector of the given target and connects to the target if
+// needed. When the connection fails or the circuit breaker is open, the
+// collector is returned together with the error (ErrBreakerOpen in the latter
+// case), such that it can still report the target as being down. The
+// connection attempt is cancelled when the context is done.
+func GetCollector(ctx context.Context, target, auth string) (*Collector, error) {
 	mu.Lock()
 	collector, ok := collectors[target]
 	if !ok {
+		evictOldest()
 		collector = NewCollector()
 		collectors[target] = collector
 	}
+	collector.used = time.Now()
 	mu.Unlock()
 
 	// Do not act concurrently on the same host
 	collector.collected.L.Lock()
 	defer collector.collected.L.Unlock()
 
+	// A running collection uses the client without holding the lock
+	for collector.client == nil && collector.collecting {
+		collector.collected.Wait()
+	}
+
 	// Try to instantiate a new Redfish host
 	if collector.client == nil {
-		auth := config.GetAuthConfig(target, auth)
-		if auth == nil {
+		settings := config.GetAuthConfig(target, auth)
+		if settings == nil {
 			return nil, fmt.Errorf("could not find login credentials")
 		}
-		c := NewClient(target, auth)
+		if !collector.breaker.Allow() {
+			return collector, ErrBreakerOpen
+		}
+		c := NewClient(ctx, target, settings)
 		if c == nil {
-			return nil, fmt.Errorf("failed to instantiate new client")
+			collector.breaker.Failure(target)
+			return collector, fmt.Errorf("failed to instantiate new client")
 		} else {
+			collector.breaker.Success(target)
 			collector.client = c
+			mu.Lock()
+			collector.auth = auth
+			collector.settings = settings
+			mu.Unlock()
 		}
 	}
 
@@ -0,0 +1,96 @@
+package collector
+
+import (
+	"time"
+
+	"github.com/mrlhansen/idrac_exporter/internal/config"
+	"github.com/mrlhansen/idrac_exporter/internal/log"
+)
+
+// StartEviction periodically removes the collectors of targets that have not
+// been scraped within the idle timeout, and deletes their sessions.
+func StartEviction() {
+	go func() {
+		for {
+			interval := min(config.GetCollectorsConfig().IdleTimeoutDuration, time.Minute)
+			if interval <= 0 {
+				interval = time.Minute
+			}
+			time.Sleep(interval)
+			evictIdle()
+		}
+	}()
+}
+
+func evictIdle() {
+	timeout := config.GetCollectorsConfig().IdleTimeoutDuration
+	if timeout <= 0 {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for target, c := range collectors {
+		if time.Since(c.used) < timeout || c.running.Load() {
+			continue
+		}
+		log.Debug("Host %s has not been scraped for %s, removing collector", target, timeout)
+		evict(target)
+	}
+}
+
+// Removes the least recently used collector when the maximum number of
+// collectors is reached. Polled targets are not counted and never removed,
+// since they would be added again by the next poll. Collectors that are
+// collecting metrics are not removed either. Must be called with mu held.
+func evictOldest() {
+	size := config.GetCollectorsConfig().MaxSize
+	if size == 0 {
+		return
+	}
+
+	var oldest string
+	var used time.Time
+	n := 0
+	for target, c := range collectors {
+		if IsPolled(target) {
+			continue
+		}
+		n++
+		if c.running.Load() {
+			continue
+		}
+		if oldest == "" || c.used.Before(used) {
+			oldest = target
+			used = c.used
+		}
+	}
+
+	if n < int(size) {
+		return
+	}
+
+	if oldest == "" {
+		log.Debug("Maximum number of collectors reached, but all of them are collecting metrics")
+		return
+	}
+
+	log.Debug("Maximum number of collectors reached, removing collector of host %s", oldest)
+	evict(oldest)
+}
+
+// Removes the collector of the target and closes it in the background. Must be
+// called with mu held.
+func evict(target string) {
+	c := collectors[target]
+	delete(collectors, target)
+	go c.Close()
+}
+
+// Count returns the number of live collectors
+func Count() int {
+	mu.Lock()
+	defer mu.Unlock()
+	return len(collectors)
+}
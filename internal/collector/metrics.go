@@ -1,7 +1,12 @@
 package collector
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -37,6 +42,98 @@ func linkstatus2value(status string) int {
 	return 0
 }
 
+func (mc *Collector) NewExporterSelectQuery(ch chan<- prometheus.Metric, enabled bool) {
+	var value float64
+	if enabled {
+		value = 1
+	}
+	ch <- prometheus.MustNewConstMetric(
+		mc.ExporterSelectQuery,
+		prometheus.GaugeValue,
+		value,
+	)
+}
+
+func (mc *Collector) NewExporterTruncatedTotal(ch chan<- prometheus.Metric, count uint64) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.ExporterTruncatedTotal,
+		prometheus.CounterValue,
+		float64(count),
+	)
+}
+
+func (mc *Collector) NewExporterCacheStats(ch chan<- prometheus.Metric, hits, misses uint64) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.ExporterCacheHitsTotal,
+		prometheus.CounterValue,
+		float64(hits),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		mc.ExporterCacheMissesTotal,
+		prometheus.CounterValue,
+		float64(misses),
+	)
+}
+
+func (mc *Collector) NewExporterRetriesTotal(ch chan<- prometheus.Metric, count uint64) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.ExporterRetriesTotal,
+		prometheus.CounterValue,
+		float64(count),
+	)
+}
+
+func (mc *Collector) NewUp(ch chan<- prometheus.Metric, up bool) {
+	var value float64
+	if up {
+		value = 1
+	}
+	ch <- prometheus.MustNewConstMetric(
+		mc.Up,
+		prometheus.GaugeValue,
+		value,
+	)
+}
+
+func (mc *Collector) NewExporterBreakerState(ch chan<- prometheus.Metric, state BreakerState) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.ExporterBreakerState,
+		prometheus.GaugeValue,
+		float64(state),
+	)
+}
+
+func (mc *Collector) NewCollectorSuccess(ch chan<- prometheus.Metric, group string, ok bool) {
+	var value float64
+	if ok {
+		value = 1
+	}
+	ch <- prometheus.MustNewConstMetric(
+		mc.CollectorSuccess,
+		prometheus.GaugeValue,
+		value,
+		group,
+	)
+}
+
+func (mc *Collector) NewCollectorDuration(ch chan<- prometheus.Metric, group string, duration time.Duration) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.CollectorDuration,
+		prometheus.GaugeValue,
+		duration.Seconds(),
+		group,
+	)
+}
+
+func (mc *Collector) NewCollectorCacheAge(ch chan<- prometheus.Metric, group string, age time.Duration) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.CollectorCacheAge,
+		prometheus.GaugeValue,
+		age.Seconds(),
+		group,
+	)
+}
+
 func (mc *Collector) NewSystemPowerOn(ch chan<- prometheus.Metric, m *SystemResponse) {
 	var value float64
 	if m.PowerState == "On" {
@@ -135,18 +232,19 @@ func (mc *Collector) NewSystemMachineInfo(ch chan<- prometheus.Metric, m *System
 	)
 }
 
-func (mc *Collector) NewSensorsTemperature(ch chan<- prometheus.Metric, temperature float64, id, name, units string) {
+func (mc *Collector) NewSensorsTemperature(ch chan<- prometheus.Metric, temperature float64, id, name, context, units string) {
 	ch <- prometheus.MustNewConstMetric(
 		mc.SensorsTemperature,
 		prometheus.GaugeValue,
 		temperature,
 		id,
 		name,
+		context,
 		units,
 	)
 }
 
-func (mc *Collector) NewSensorsFanHealth(ch chan<- prometheus.Metric, id, name, health string) {
+func (mc *Collector) NewSensorsFanHealth(ch chan<- prometheus.Metric, id, name, context, health string) {
 	value := health2value(health)
 	if value < 0 {
 		return
@@ -157,29 +255,198 @@ func (mc *Collector) NewSensorsFanHealth(ch chan<- prometheus.Metric, id, name,
 		float64(value),
 		id,
 		name,
+		context,
 		health,
 	)
 }
 
-func (mc *Collector) NewSensorsFanSpeed(ch chan<- prometheus.Metric, speed float64, id, name, units string) {
+func (mc *Collector) NewSensorsTemperatureHealth(ch chan<- prometheus.Metric, id, name, context, health string) {
+	value := health2value(health)
+	if value < 0 {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(
+		mc.SensorsTemperatureHealth,
+		prometheus.GaugeValue,
+		float64(value),
+		id,
+		name,
+		context,
+		health,
+	)
+}
+
+func (mc *Collector) NewSensorsVoltageHealth(ch chan<- prometheus.Metric, id, name, context, health string) {
+	value := health2value(health)
+	if value < 0 {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(
+		mc.SensorsVoltageHealth,
+		prometheus.GaugeValue,
+		float64(value),
+		id,
+		name,
+		context,
+		health,
+	)
+}
+
+func (mc *Collector) NewSensorsFanSpeed(ch chan<- prometheus.Metric, speed float64, id, name, context, units string) {
 	ch <- prometheus.MustNewConstMetric(
 		mc.SensorsFanSpeed,
 		prometheus.GaugeValue,
 		speed,
 		id,
 		name,
+		context,
 		units,
 	)
 }
 
-func (mc *Collector) NewSensorsVoltage(ch chan<- prometheus.Metric, voltage float64, id, name, units string) {
+func (mc *Collector) NewSensorsVoltage(ch chan<- prometheus.Metric, voltage float64, id, name, context, units string) {
 	ch <- prometheus.MustNewConstMetric(
 		mc.SensorsVoltage,
 		prometheus.GaugeValue,
 		voltage,
 		id,
 		name,
+		context,
+		units,
+	)
+}
+
+func (mc *Collector) NewSensorsTemperatureThreshold(ch chan<- prometheus.Metric, value float64, id, name, context, units, kind string) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.SensorsTemperatureThreshold,
+		prometheus.GaugeValue,
+		value,
+		id,
+		name,
+		context,
 		units,
+		kind,
+	)
+}
+
+func (mc *Collector) NewSensorsFanSpeedThreshold(ch chan<- prometheus.Metric, value float64, id, name, context, units, kind string) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.SensorsFanSpeedThreshold,
+		prometheus.GaugeValue,
+		value,
+		id,
+		name,
+		context,
+		units,
+		kind,
+	)
+}
+
+func (mc *Collector) NewSensorsVoltageThreshold(ch chan<- prometheus.Metric, value float64, id, name, context, units, kind string) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.SensorsVoltageThreshold,
+		prometheus.GaugeValue,
+		value,
+		id,
+		name,
+		context,
+		units,
+		kind,
+	)
+}
+
+func (mc *Collector) NewSensorsPower(ch chan<- prometheus.Metric, power float64, id, name, context, units string) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.SensorsPower,
+		prometheus.GaugeValue,
+		power,
+		id,
+		name,
+		context,
+		units,
+	)
+}
+
+func (mc *Collector) NewSensorsCurrent(ch chan<- prometheus.Metric, current float64, id, name, context, units string) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.SensorsCurrent,
+		prometheus.GaugeValue,
+		current,
+		id,
+		name,
+		context,
+		units,
+	)
+}
+
+func (mc *Collector) NewSensorsHumidity(ch chan<- prometheus.Metric, humidity float64, id, name, context, units string) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.SensorsHumidity,
+		prometheus.GaugeValue,
+		humidity,
+		id,
+		name,
+		context,
+		units,
+	)
+}
+
+func (mc *Collector) NewSensorsAirflow(ch chan<- prometheus.Metric, airflow float64, id, name, context, units string) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.SensorsAirflow,
+		prometheus.GaugeValue,
+		airflow,
+		id,
+		name,
+		context,
+		units,
+	)
+}
+
+func (mc *Collector) NewRedundancyHealth(ch chan<- prometheus.Metric, m *Redundancy, id, kind string) {
+	value := health2value(m.Status.Health)
+	if value < 0 {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(
+		mc.RedundancyHealth,
+		prometheus.GaugeValue,
+		float64(value),
+		id,
+		m.Name,
+		m.Mode.String(),
+		kind,
+		m.Status.Health,
+	)
+}
+
+func (mc *Collector) NewRedundancyMinNeeded(ch chan<- prometheus.Metric, m *Redundancy, id, kind string) {
+	if m.MinNumNeeded == nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(
+		mc.RedundancyMinNeeded,
+		prometheus.GaugeValue,
+		float64(*m.MinNumNeeded),
+		id,
+		m.Name,
+		m.Mode.String(),
+		kind,
+	)
+}
+
+func (mc *Collector) NewRedundancyMaxSupported(ch chan<- prometheus.Metric, m *Redundancy, id, kind string) {
+	if m.MaxNumSupported == nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(
+		mc.RedundancyMaxSupported,
+		prometheus.GaugeValue,
+		float64(*m.MaxNumSupported),
+		id,
+		m.Name,
+		m.Mode.String(),
+		kind,
 	)
 }
 
@@ -866,6 +1133,112 @@ func (mc *Collector) NewManagerHealth(ch chan<- prometheus.Metric, m *ManagerRes
 	)
 }
 
+func (mc *Collector) NewBmcTLSInfo(ch chan<- prometheus.Metric, state *tls.ConnectionState) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.BmcTLSInfo,
+		prometheus.UntypedValue,
+		1.0,
+		tls.VersionName(state.Version),
+		tls.CipherSuiteName(state.CipherSuite),
+		state.ServerName,
+	)
+}
+
+func (mc *Collector) NewBmcPeerCertificate(ch chan<- prometheus.Metric, cert *x509.Certificate) {
+	subject := cert.Subject.CommonName
+	issuer := cert.Issuer.CommonName
+	serial := formatSerial(cert.SerialNumber)
+
+	ch <- prometheus.MustNewConstMetric(
+		mc.BmcCertInfo,
+		prometheus.UntypedValue,
+		1.0,
+		"tls",
+		"",
+		subject,
+		issuer,
+		serial,
+		certificateSANs(cert),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		mc.BmcCertExpiry,
+		prometheus.GaugeValue,
+		float64(cert.NotAfter.Unix()),
+		"tls",
+		"",
+		subject,
+		issuer,
+		serial,
+	)
+}
+
+func (mc *Collector) NewBmcCertificate(ch chan<- prometheus.Metric, c *Certificate) {
+	sans := ""
+	serial := normalizeSerial(c.SerialNumber)
+
+	// The subject alternative names are only available in the certificate itself
+	block, _ := pem.Decode([]byte(c.CertificateString))
+	if block != nil {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			sans = certificateSANs(cert)
+			serial = formatSerial(cert.SerialNumber)
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		mc.BmcCertInfo,
+		prometheus.UntypedValue,
+		1.0,
+		"redfish",
+		c.OdataId,
+		c.Subject.CommonName,
+		c.Issuer.CommonName,
+		serial,
+		sans,
+	)
+
+	t, err := time.Parse(time.RFC3339, c.ValidNotAfter)
+	if err != nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		mc.BmcCertExpiry,
+		prometheus.GaugeValue,
+		float64(t.Unix()),
+		"redfish",
+		c.OdataId,
+		c.Subject.CommonName,
+		c.Issuer.CommonName,
+		serial,
+	)
+}
+
+// Formats the serial number of a certificate as lower case hex digits without
+// separators, which is the format used for all certificate metrics
+func formatSerial(n *big.Int) string {
+	return n.Text(16)
+}
+
+// Converts a serial number reported by Redfish (e.g. "0A:1B:2C") to the same
+// format as formatSerial. Serial numbers that are not hex are kept as they are.
+func normalizeSerial(s string) string {
+	n, ok := new(big.Int).SetString(strings.NewReplacer(":", "", " ", "", "-", "").Replace(s), 16)
+	if !ok {
+		return s
+	}
+	return formatSerial(n)
+}
+
+func certificateSANs(cert *x509.Certificate) string {
+	sans := slices.Clone(cert.DNSNames)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return strings.Join(sans, ",")
+}
+
 func (mc *Collector) NewPduInfo(ch chan<- prometheus.Metric, id string, m *PowerDistribution) {
 	ch <- prometheus.MustNewConstMetric(
 		mc.PduInfo,
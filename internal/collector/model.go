@@ -54,9 +54,11 @@ type Status struct {
 
 // Redundancy is a common structure used in any entity with redundancy
 type Redundancy struct {
+	OdataId           string  `json:"@odata.id"`
+	MemberId          string  `json:"MemberId"`
 	Name              string  `json:"Name"`
-	MaxNumSupported   int     `json:"MaxNumSupported"`
-	MinNumNeeded      int     `json:"MinNumNeeded"`
+	MaxNumSupported   *int    `json:"MaxNumSupported"`
+	MinNumNeeded      *int    `json:"MinNumNeeded"`
 	Mode              xstring `json:"Mode"`
 	RedundancyEnabled bool    `json:"RedundancyEnabled"`
 	RedundancySet     []any   `json:"RedundancySet"`
@@ -74,6 +76,36 @@ type Threshold struct {
 	UpperThresholdNonCritical any `json:"UpperThresholdNonCritical"`
 }
 
+type ThresholdValue struct {
+	Type  string
+	Value float64
+}
+
+// Values returns the thresholds that are reported as numbers, where values
+// such as N/A are skipped
+func (t *Threshold) Values() []ThresholdValue {
+	var list []ThresholdValue
+
+	for _, v := range []struct {
+		kind  string
+		value any
+	}{
+		{"lower_non_critical", t.LowerThresholdNonCritical},
+		{"lower_critical", t.LowerThresholdCritical},
+		{"lower_fatal", t.LowerThresholdFatal},
+		{"upper_non_critical", t.UpperThresholdNonCritical},
+		{"upper_critical", t.UpperThresholdCritical},
+		{"upper_fatal", t.UpperThresholdFatal},
+	} {
+		f, ok := asFloat64(v.value)
+		if ok {
+			list = append(list, ThresholdValue{Type: v.kind, Value: f})
+		}
+	}
+
+	return list
+}
+
 // V1Response represents structure of the response body from /redfish/v1
 type V1Response struct {
 	RedfishVersion            string `json:"RedfishVersion"`
@@ -124,6 +156,17 @@ type GroupResponse struct {
 	Members     OdataSlice `json:"Members"`
 }
 
+// CollectionResponse is used for collections where the members are decoded
+// separately, because they might have been expanded by the service
+type CollectionResponse struct {
+	Id          string            `json:"Id"`
+	Name        string            `json:"Name"`
+	Description string            `json:"Description"`
+	Members     []json.RawMessage `json:"Members"`
+	Count       int               `json:"Members@odata.count"`
+	NextLink    string            `json:"Members@odata.nextLink"`
+}
+
 type Processor struct {
 	Id                    string  `json:"Id"`
 	Name                  string  `json:"Name"`
@@ -170,6 +213,7 @@ type Processor struct {
 }
 
 type ChassisResponse struct {
+	Id                      string `json:"Id"`
 	Name                    string `json:"Name"`
 	AssetTag                string `json:"AssetTag"`
 	SerialNumber            string `json:"SerialNumber"`
@@ -328,6 +372,59 @@ type ThermalMetrics struct {
 	} `json:"TemperatureSummaryCelsius"`
 }
 
+// Sensor is a single reading from the Sensors collection of a chassis
+type Sensor struct {
+	Id              string           `json:"Id"`
+	Name            string           `json:"Name"`
+	ReadingType     string           `json:"ReadingType"`
+	ReadingUnits    string           `json:"ReadingUnits"`
+	Reading         *float64         `json:"Reading"`
+	PhysicalContext string           `json:"PhysicalContext"`
+	Status          Status           `json:"Status"`
+	Thresholds      SensorThresholds `json:"Thresholds"`
+}
+
+type SensorThresholds struct {
+	LowerCaution  *SensorThreshold `json:"LowerCaution"`
+	LowerCritical *SensorThreshold `json:"LowerCritical"`
+	LowerFatal    *SensorThreshold `json:"LowerFatal"`
+	UpperCaution  *SensorThreshold `json:"UpperCaution"`
+	UpperCritical *SensorThreshold `json:"UpperCritical"`
+	UpperFatal    *SensorThreshold `json:"UpperFatal"`
+}
+
+type SensorThreshold struct {
+	Reading any `json:"Reading"`
+}
+
+// Values returns the thresholds of the sensor, using the same types as the
+// thresholds in the Thermal and Power resources (caution is non-critical)
+func (t *SensorThresholds) Values() []ThresholdValue {
+	var list []ThresholdValue
+
+	for _, v := range []struct {
+		kind  string
+		value *SensorThreshold
+	}{
+		{"lower_non_critical", t.LowerCaution},
+		{"lower_critical", t.LowerCritical},
+		{"lower_fatal", t.LowerFatal},
+		{"upper_non_critical", t.UpperCaution},
+		{"upper_critical", t.UpperCritical},
+		{"upper_fatal", t.UpperFatal},
+	} {
+		if v.value == nil {
+			continue
+		}
+		f, ok := asFloat64(v.value.Reading)
+		if ok {
+			list = append(list, ThresholdValue{Type: v.kind, Value: f})
+		}
+	}
+
+	return list
+}
+
 type Storage struct {
 	Id                 string              `json:"Id"`
 	Name               string              `json:"Name"`
@@ -514,6 +611,7 @@ type NetworkPort struct {
 }
 
 type SystemResponse struct {
+	Id                      string `json:"Id"`
 	IndicatorLED            string `json:"IndicatorLED"`
 	LocationIndicatorActive *bool  `json:"LocationIndicatorActive"`
 	Manufacturer            string `json:"Manufacturer"`
@@ -770,25 +868,20 @@ type PowerSupplyMetrics struct {
 	} `json:"FrequencyHz"`
 }
 
-type EventLogResponse struct {
-	Id          string `json:"Id"`
-	Name        string `json:"Name"`
-	Description string `json:"Description"`
-	Members     []struct {
-		Id           string  `json:"Id"`
-		EventId      string  `json:"EventId"`
-		Name         string  `json:"Name"`
-		Created      string  `json:"Created"`
-		Description  string  `json:"Description"`
-		EntryCode    xstring `json:"EntryCode"`
-		EntryType    string  `json:"EntryType"`
-		Message      string  `json:"Message"`
-		MessageArgs  []any   `json:"MessageArgs"`
-		MessageId    string  `json:"MessageId"`
-		SensorNumber int     `json:"SensorNumber"`
-		SensorType   xstring `json:"SensorType"`
-		Severity     string  `json:"Severity"`
-	} `json:"Members"`
+type EventLogEntry struct {
+	Id           string  `json:"Id"`
+	EventId      string  `json:"EventId"`
+	Name         string  `json:"Name"`
+	Created      string  `json:"Created"`
+	Description  string  `json:"Description"`
+	EntryCode    xstring `json:"EntryCode"`
+	EntryType    string  `json:"EntryType"`
+	Message      string  `json:"Message"`
+	MessageArgs  []any   `json:"MessageArgs"`
+	MessageId    string  `json:"MessageId"`
+	SensorNumber int     `json:"SensorNumber"`
+	SensorType   xstring `json:"SensorType"`
+	Severity     string  `json:"Severity"`
 }
 
 type ManagerResponse struct {
@@ -801,6 +894,7 @@ type ManagerResponse struct {
 	ServiceIdentification string `json:"ServiceIdentification"`
 	TimeZoneName          string `json:"TimeZoneName"`
 	Status                Status `json:"Status"`
+	NetworkProtocol       Odata  `json:"NetworkProtocol"`
 	Links                 struct {
 		Oem struct {
 			Dell struct {
@@ -810,10 +904,53 @@ type ManagerResponse struct {
 	} `json:"Links"`
 }
 
+type NetworkProtocolResponse struct {
+	Id    string `json:"Id"`
+	Name  string `json:"Name"`
+	HTTPS struct {
+		Port            int   `json:"Port"`
+		ProtocolEnabled bool  `json:"ProtocolEnabled"`
+		Certificates    Odata `json:"Certificates"`
+	} `json:"HTTPS"`
+}
+
+type CertificateServiceResponse struct {
+	Id                   string `json:"Id"`
+	Name                 string `json:"Name"`
+	CertificateLocations Odata  `json:"CertificateLocations"`
+}
+
+type CertificateLocationsResponse struct {
+	Id    string `json:"Id"`
+	Name  string `json:"Name"`
+	Links struct {
+		Certificates OdataSlice `json:"Certificates"`
+	} `json:"Links"`
+}
+
+type CertificateIdentifier struct {
+	CommonName         string `json:"CommonName"`
+	Organization       string `json:"Organization"`
+	OrganizationalUnit string `json:"OrganizationalUnit"`
+	Country            string `json:"Country"`
+}
+
+type Certificate struct {
+	OdataId           string                `json:"@odata.id"`
+	Id                string                `json:"Id"`
+	Name              string                `json:"Name"`
+	CertificateString string                `json:"CertificateString"`
+	CertificateType   string                `json:"CertificateType"`
+	SerialNumber      string                `json:"SerialNumber"`
+	Subject           CertificateIdentifier `json:"Subject"`
+	Issuer            CertificateIdentifier `json:"Issuer"`
+	ValidNotBefore    string                `json:"ValidNotBefore"`
+	ValidNotAfter     string                `json:"ValidNotAfter"`
+}
+
 // Dell OEM
 const (
-	DellSystemPath     string = "/redfish/v1/Systems/System.Embedded.1/Oem/Dell/DellSystem/System.Embedded.1"
-	DellAttributesPath string = "/redfish/v1/Managers/iDRAC.Embedded.1/Oem/Dell/DellAttributes/iDRAC.Embedded.1"
+	DellSystemPath string = "/redfish/v1/Systems/System.Embedded.1/Oem/Dell/DellSystem/System.Embedded.1"
 )
 
 type DellSystem struct {
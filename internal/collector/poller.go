@@ -0,0 +1,107 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mrlhansen/idrac_exporter/internal/config"
+	"github.com/mrlhansen/idrac_exporter/internal/log"
+)
+
+// Targets for which a background collection is currently running
+var polling sync.Map
+
+// StartPolling periodically collects metrics in the background from all hosts
+// in the configuration, such that scrapes can be served from the last result.
+func StartPolling() {
+	log.Info("Background polling enabled with interval %s", config.Config.Poll.Interval)
+
+	go func() {
+		for {
+			pollTargets()
+			time.Sleep(config.Config.Poll.IntervalDuration)
+		}
+	}()
+}
+
+func pollTargets() {
+	for _, target := range polledTargets() {
+		_, busy := polling.LoadOrStore(target, true)
+		if busy {
+			log.Debug("Skipping poll of host %s, previous poll is still running", target)
+			continue
+		}
+
+		go func() {
+			defer polling.Delete(target)
+
+			c, err := GetCollector(context.Background(), target, "")
+			if err != nil {
+				if errors.Is(err, ErrBreakerOpen) {
+					log.Debug("Circuit breaker for host %s is open, skipping connection attempt", target)
+				} else {
+					log.Error("Error instantiating metrics collector for host %s: %v", target, err)
+				}
+				if c == nil {
+					return
+				}
+			}
+
+			collect, _ := config.GetCollectConfig(target, "", "")
+			metrics, err := c.Gather(context.Background(), collect)
+			if err != nil {
+				log.Error("Error collecting metrics for host %s: %v", target, err)
+				return
+			}
+
+			// Only background collections update the snapshot, since scrapes
+			// may select other metrics groups or auth groups
+			c.setSnapshot(metrics)
+		}()
+	}
+}
+
+func polledTargets() []string {
+	var list []string
+
+	config.Config.Mutex.RLock()
+	defer config.Config.Mutex.RUnlock()
+
+	for t := range config.Config.Hosts {
+		if t == "default" || config.IsPattern(t) {
+			continue
+		}
+		list = append(list, t)
+	}
+
+	return list
+}
+
+// IsPolled reports whether metrics for the target are collected in the background
+func IsPolled(target string) bool {
+	if !config.Config.Poll.Enabled || target == "default" {
+		return false
+	}
+
+	config.Config.Mutex.RLock()
+	defer config.Config.Mutex.RUnlock()
+
+	_, ok := config.Config.Hosts[target]
+	return ok
+}
+
+// GetSnapshot returns the metrics from the last background collection for
+// the target. The second return value is false when there is no result yet.
+func GetSnapshot(target string) (string, bool) {
+	mu.Lock()
+	collector, ok := collectors[target]
+	mu.Unlock()
+
+	if !ok {
+		return "", false
+	}
+
+	return collector.Snapshot()
+}
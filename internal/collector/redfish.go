@@ -5,12 +5,20 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
+	"net"
 	"net/http"
 	neturl "net/url"
 	"path"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/mrlhansen/idrac_exporter/internal/config"
@@ -25,68 +33,202 @@ type RedfishSession struct {
 }
 
 type Redfish struct {
-	baseurl  string
-	hostname string
-	username string
-	password string
-	session  RedfishSession
-	http     *http.Client
-	sem      *semaphore.Weighted
-	ctx      context.Context
+	baseurl   string
+	hostname  string
+	auth      *config.AuthConfig
+	creds     sync.RWMutex // protects the credentials and the session token
+	username  string
+	password  string
+	certAuth  bool
+	session   RedfishSession
+	http      *http.Client
+	sem       *semaphore.Weighted
+	ctx       atomic.Pointer[context.Context]
+	expand    string
+	noExpand  atomic.Bool
+	selects   bool
+	noSelect  atomic.Bool
+	truncated atomic.Uint64
+	etags     struct {
+		sync.Mutex
+		entries map[string]etagEntry
+		size    int // total size of the cached bodies
+	}
+	hits     atomic.Uint64
+	misses   atomic.Uint64
+	retries  atomic.Uint64
+	tlsState atomic.Pointer[tls.ConnectionState]
+}
+
+// Cached response body of a resource that was returned with an ETag
+type etagEntry struct {
+	etag string
+	body []byte
 }
 
 const redfishRootPath = "/redfish/v1"
 
+// Maximum total size of the response bodies in the ETag cache of a host
+const maxEtagCacheSize = 8 << 20
+
+var errUnauthorized = errors.New("401 Unauthorized")
+
+// statusError is returned for responses with an unexpected status code
+type statusError struct {
+	url    string
+	code   int
+	status string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status code from %q: %s", e.url, e.status)
+}
+
 func NewRedfish(host string, auth *config.AuthConfig) *Redfish {
-	cfg := config.Config
+	var username, password string
+	var err error
+
+	if !auth.CertAuth() {
+		username, password, err = auth.Credentials(host)
+		if err != nil {
+			log.Error("Could not get login credentials for %s: %v", host, err)
+			return nil
+		}
+	}
+
 	baseurl := fmt.Sprintf("%s://%s", auth.Scheme, host)
 	if auth.Port > 0 {
 		baseurl = fmt.Sprintf("%s:%d", baseurl, auth.Port)
 	}
-	return &Redfish{
+	r := &Redfish{
 		baseurl:  baseurl,
 		hostname: host,
-		username: auth.Username,
-		password: auth.Password,
+		auth:     auth,
+		username: username,
+		password: password,
+		certAuth: auth.CertAuth(),
 		session: RedfishSession{
-			disabled: auth.BasicAuth,
+			disabled: auth.BasicAuth || auth.CertAuth(),
 		},
 		http: &http.Client{
 			Transport: &http.Transport{
 				Proxy:                 http.ProxyFromEnvironment,
-				TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
-				MaxIdleConnsPerHost:   int(cfg.Concurrency),                     // Allow more concurrent requests per host
-				IdleConnTimeout:       30 * time.Second,                         // Remove stale connections after 30s
-				ResponseHeaderTimeout: time.Duration(cfg.Timeout) * time.Second, // Timeout waiting for response headers
-				ExpectContinueTimeout: 1 * time.Second,                          // Timeout for 100-continue responses
+				TLSClientConfig:       newTLSConfig(&auth.TLS),
+				MaxIdleConnsPerHost:   int(auth.Concurrency),                     // Allow more concurrent requests per host
+				IdleConnTimeout:       30 * time.Second,                          // Remove stale connections after 30s
+				ResponseHeaderTimeout: time.Duration(auth.Timeout) * time.Second, // Timeout waiting for response headers
+				ExpectContinueTimeout: 1 * time.Second,                           // Timeout for 100-continue responses
 			},
-			Timeout: time.Duration(cfg.Timeout) * time.Second,
+			Timeout: time.Duration(auth.Timeout) * time.Second,
 		},
-		sem: semaphore.NewWeighted(int64(cfg.Concurrency)),
-		ctx: context.Background(),
+		sem: semaphore.NewWeighted(int64(auth.Concurrency)),
+	}
+	r.etags.entries = make(map[string]etagEntry)
+	r.SetContext(context.Background())
+	return r
+}
+
+func newTLSConfig(c *config.ClientTLSConfig) *tls.Config {
+	t := &tls.Config{
+		InsecureSkipVerify: c.InsecureSkipVerify(),
+		RootCAs:            c.RootCAs,
+		ServerName:         c.ServerName,
+		MinVersion:         c.Version,
+	}
+	if c.ClientCert != nil {
+		t.Certificates = []tls.Certificate{*c.ClientCert}
 	}
+	return t
+}
+
+// SetContext sets the context used for all subsequent requests, such that
+// outstanding requests are cancelled together with the scrape
+func (r *Redfish) SetContext(ctx context.Context) {
+	r.ctx.Store(&ctx)
+}
+
+func (r *Redfish) requestContext() context.Context {
+	return *r.ctx.Load()
+}
+
+func (r *Redfish) credentials() (string, string) {
+	r.creds.RLock()
+	defer r.creds.RUnlock()
+	return r.username, r.password
+}
+
+// authorize adds the session token or the basic auth credentials to the request
+func (r *Redfish) authorize(req *http.Request) {
+	r.creds.RLock()
+	defer r.creds.RUnlock()
+
+	if len(r.session.token) > 0 {
+		req.Header.Set("X-Auth-Token", r.session.token)
+	} else if !r.certAuth {
+		req.SetBasicAuth(r.username, r.password)
+	}
+}
+
+// ReloadCredentials reads the login credentials again (e.g. from files or the
+// secret provider) and reports whether they have changed
+func (r *Redfish) ReloadCredentials() bool {
+	if r.certAuth {
+		return false
+	}
+
+	username, password, err := r.auth.Credentials(r.hostname)
+	if err != nil {
+		log.Error("Could not get login credentials for %s: %v", r.hostname, err)
+		return false
+	}
+
+	r.creds.Lock()
+	defer r.creds.Unlock()
+
+	if username == r.username && password == r.password {
+		return false
+	}
+
+	log.Info("Login credentials for %s have changed", r.hostname)
+	r.username = username
+	r.password = password
+
+	return true
+}
+
+func (r *Redfish) hasSession() bool {
+	r.creds.RLock()
+	defer r.creds.RUnlock()
+	return len(r.session.token) > 0
 }
 
 func (r *Redfish) DisableSession() {
+	r.creds.Lock()
 	r.session.disabled = true
 	r.session.token = ""
 	r.session.id = ""
+	r.creds.Unlock()
 	log.Info("Session authentication disabled for %s due to failed creation or refresh", r.hostname)
 }
 
 func (r *Redfish) CreateSession() bool {
-	if r.session.disabled {
+	r.creds.RLock()
+	disabled := r.session.disabled
+	r.creds.RUnlock()
+
+	if disabled {
 		return false
 	}
 
 	url := fmt.Sprintf("%s/redfish/v1/SessionService/Sessions", r.baseurl)
+	username, password := r.credentials()
 	session := Session{
-		Username: r.username,
-		Password: r.password,
+		Username: username,
+		Password: password,
 	}
 	body, _ := json.Marshal(&session)
 
-	resp, err := r.http.Post(url, "application/json", bytes.NewBuffer(body))
+	resp, err := r.post(url, body)
 	defer func() {
 		if resp != nil {
 			resp.Body.Close()
@@ -108,7 +250,7 @@ func (r *Redfish) CreateSession() bool {
 		}
 
 		url = fmt.Sprintf("%s/redfish/v1/Sessions", r.baseurl)
-		resp, err = r.http.Post(url, "application/json", bytes.NewBuffer(body))
+		resp, err = r.post(url, body)
 		if err != nil {
 			r.DisableSession()
 			return false
@@ -126,34 +268,52 @@ func (r *Redfish) CreateSession() bool {
 		return false
 	}
 
-	r.session.id = session.OdataId
-	r.session.token = resp.Header.Get("X-Auth-Token")
+	id := session.OdataId
 
 	// iLO 4
-	if len(r.session.id) == 0 {
+	if len(id) == 0 {
 		u, err := neturl.Parse(resp.Header.Get("Location"))
 		if err == nil {
-			r.session.id = u.Path
+			id = u.Path
 		}
 	}
 
-	log.Debug("Succesfully created session: %s", path.Base(r.session.id))
+	r.creds.Lock()
+	r.session.id = id
+	r.session.token = resp.Header.Get("X-Auth-Token")
+	r.creds.Unlock()
+
+	log.Debug("Succesfully created session: %s", path.Base(id))
 	return true
 }
 
+// post sends a JSON request body to the given url
+func (r *Redfish) post(url string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(r.requestContext(), "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return r.http.Do(req)
+}
+
 func (r *Redfish) DeleteSession() bool {
-	if len(r.session.token) == 0 {
+	r.creds.RLock()
+	session := r.session
+	r.creds.RUnlock()
+
+	if len(session.token) == 0 {
 		return true
 	}
 
-	url := fmt.Sprintf("%s%s", r.baseurl, r.session.id)
-	req, err := http.NewRequest("DELETE", url, nil)
+	url := fmt.Sprintf("%s%s", r.baseurl, session.id)
+	req, err := http.NewRequestWithContext(r.requestContext(), "DELETE", url, nil)
 	if err != nil {
 		return false
 	}
 
 	req.Header.Add("Accept", "application/json")
-	req.Header.Set("X-Auth-Token", r.session.token)
+	req.Header.Set("X-Auth-Token", session.token)
 
 	resp, err := r.http.Do(req)
 	if resp != nil {
@@ -169,19 +329,25 @@ func (r *Redfish) DeleteSession() bool {
 		return false
 	}
 
-	log.Debug("Succesfully deleted session: %s", path.Base(r.session.id))
+	log.Debug("Succesfully deleted session: %s", path.Base(session.id))
+	r.creds.Lock()
 	r.session.id = ""
 	r.session.token = ""
+	r.creds.Unlock()
 
 	return true
 }
 
 func (r *Redfish) RefreshSession() bool {
-	if r.session.disabled {
+	r.creds.RLock()
+	session := r.session
+	r.creds.RUnlock()
+
+	if session.disabled {
 		return false
 	}
 
-	if len(r.session.token) == 0 {
+	if len(session.token) == 0 {
 		ok := r.CreateSession()
 		if !ok {
 			r.DisableSession()
@@ -189,14 +355,14 @@ func (r *Redfish) RefreshSession() bool {
 		return ok
 	}
 
-	url := fmt.Sprintf("%s%s", r.baseurl, r.session.id)
-	req, err := http.NewRequest("GET", url, nil)
+	url := fmt.Sprintf("%s%s", r.baseurl, session.id)
+	req, err := http.NewRequestWithContext(r.requestContext(), "GET", url, nil)
 	if err != nil {
 		return false
 	}
 
 	req.Header.Add("Accept", "application/json")
-	req.Header.Set("X-Auth-Token", r.session.token)
+	req.Header.Set("X-Auth-Token", session.token)
 
 	resp, err := r.http.Do(req)
 	if resp != nil {
@@ -207,6 +373,7 @@ func (r *Redfish) RefreshSession() bool {
 	}
 
 	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound {
+		r.ReloadCredentials()
 		ok := r.CreateSession()
 		if !ok {
 			r.DisableSession()
@@ -221,24 +388,105 @@ func (r *Redfish) RefreshSession() bool {
 }
 
 func (r *Redfish) Get(path string, res any) bool {
+	return r.get(path, res) == nil
+}
+
+// get fetches and decodes the resource at the given path like Get, but returns
+// the error of the last attempt, which has already been logged
+func (r *Redfish) get(path string, res any) error {
 	if !strings.HasPrefix(path, redfishRootPath) {
-		return false
+		return fmt.Errorf("invalid path %q", path)
 	}
 
-	r.sem.Acquire(r.ctx, 1)
+	url := fmt.Sprintf("%s%s", r.baseurl, path)
+	policy := config.GetRetryConfig()
+	ctx := r.requestContext()
+
+	var body []byte
+	var reloaded bool
+	for attempt := 1; ; attempt++ {
+		var retry bool
+		var after time.Duration
+		var err error
+
+		body, retry, after, err = r.fetch(ctx, path)
+		if err == nil {
+			break
+		}
+
+		// The credentials might have been changed since they were read, in
+		// which case the session has to be created again with the new ones
+		if errors.Is(err, errUnauthorized) && !reloaded && r.ReloadCredentials() {
+			reloaded = true
+			if r.hasSession() && !r.CreateSession() {
+				r.DisableSession()
+			}
+			continue
+		}
+
+		// The scrape was cancelled or timed out
+		if ctx.Err() != nil {
+			log.Debug("Request for %q cancelled: %v", url, context.Cause(ctx))
+			return ctx.Err()
+		}
+
+		if !retry || attempt >= int(policy.MaxAttempts) {
+			log.Error("%v", err)
+			return err
+		}
+
+		delay := retryDelay(attempt, after)
+		log.Debug("%v, retrying in %s (attempt %d of %d)", err, delay, attempt+1, policy.MaxAttempts)
+		r.retries.Add(1)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			log.Debug("Request for %q cancelled: %v", url, context.Cause(ctx))
+			return ctx.Err()
+		}
+	}
+
+	if config.Debug {
+		log.Debug("Response from %q: %s", url, body)
+	}
+
+	// Issue #192
+	body = bytes.ReplaceAll(body, []byte("\r"), []byte(""))
+
+	err := json.Unmarshal(body, res)
+	if err != nil {
+		log.Error("Error decoding response from %q: %v", url, err)
+		return err
+	}
+
+	return nil
+}
+
+// fetch performs a single request for the resource at the given path and
+// returns the response body. On failure, retry reports whether the error is
+// transient, and after holds the delay requested by a Retry-After header.
+func (r *Redfish) fetch(ctx context.Context, path string) (body []byte, retry bool, after time.Duration, err error) {
+	err = r.sem.Acquire(ctx, 1)
+	if err != nil {
+		return nil, false, 0, err
+	}
 	defer r.sem.Release(1)
 
 	url := fmt.Sprintf("%s%s", r.baseurl, path)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return false
+		return nil, false, 0, err
 	}
 
 	req.Header.Add("Accept", "application/json")
-	if len(r.session.token) > 0 {
-		req.Header.Set("X-Auth-Token", r.session.token)
-	} else {
-		req.SetBasicAuth(r.username, r.password)
+	r.authorize(req)
+
+	r.etags.Lock()
+	cached, ok := r.etags.entries[path]
+	r.etags.Unlock()
+	if ok {
+		req.Header.Set("If-None-Match", cached.etag)
 	}
 
 	log.Debug("Querying %q", url)
@@ -247,35 +495,134 @@ func (r *Redfish) Get(path string, res any) bool {
 		defer resp.Body.Close()
 	}
 	if err != nil {
-		log.Error("Failed to query %q: %v", url, err)
-		return false
+		return nil, ctx.Err() == nil && transient(err), 0, fmt.Errorf("failed to query %q: %v", url, err)
+	}
+
+	if resp.TLS != nil {
+		r.tlsState.Store(resp.TLS)
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		log.Debug("Resource %q not modified, using cached response", url)
+		r.hits.Add(1)
+		return cached.body, false, 0, nil
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, false, 0, fmt.Errorf("unexpected status code from %q: %w", url, errUnauthorized)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		log.Error("Unexpected status code from %q: %s", url, resp.Status)
-		return false
+		retry = slices.Contains(config.GetRetryConfig().StatusCodes, resp.StatusCode)
+		after = retryAfter(resp.Header.Get("Retry-After"))
+		return nil, retry, after, &statusError{url: url, code: resp.StatusCode, status: resp.Status}
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err = io.ReadAll(resp.Body)
 	if err != nil {
-		log.Error("Error reading response from %q: %v", url, err)
-		return false
+		return nil, ctx.Err() == nil && transient(err), 0, fmt.Errorf("error reading response from %q: %v", url, err)
 	}
 
-	if config.Debug {
-		log.Debug("Response from %q: %s", url, body)
+	r.misses.Add(1)
+	r.cacheResponse(path, resp.Header.Get("ETag"), body)
+
+	return body, false, 0, nil
+}
+
+// transient reports whether a failed request can be retried, which is only the
+// case for timeouts and connections that were closed by the host. Other errors,
+// e.g. a certificate that cannot be verified, would occur again.
+func transient(err error) bool {
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
 	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
 
-	// Issue #192
-	body = bytes.ReplaceAll(body, []byte("\r"), []byte(""))
+// retryDelay returns the delay before the next attempt, which is either the
+// delay requested by the server or an exponential backoff with jitter. Both
+// are limited by the configured maximum backoff.
+func retryDelay(attempt int, after time.Duration) time.Duration {
+	policy := config.GetRetryConfig()
+
+	delay := after
+	if delay == 0 {
+		delay = policy.BackoffDuration << (attempt - 1)
+		if delay <= 0 || delay > policy.MaxBackoffDuration {
+			delay = policy.MaxBackoffDuration
+		}
+		delay = delay/2 + rand.N(delay/2+1)
+	}
 
-	err = json.Unmarshal(body, res)
-	if err != nil {
-		log.Error("Error decoding response from %q: %v", url, err)
-		return false
+	return min(delay, policy.MaxBackoffDuration)
+}
+
+// retryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or a HTTP date
+func retryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
 	}
 
-	return true
+	n, err := strconv.Atoi(value)
+	if err == nil {
+		return max(time.Duration(n)*time.Second, 0)
+	}
+
+	t, err := http.ParseTime(value)
+	if err == nil {
+		return max(time.Until(t), 0)
+	}
+
+	return 0
+}
+
+// TLSState returns the state of the most recent TLS connection to the host,
+// or nil when TLS is not used
+func (r *Redfish) TLSState() *tls.ConnectionState {
+	return r.tlsState.Load()
+}
+
+// Retries returns the number of requests that have been retried
+func (r *Redfish) Retries() uint64 {
+	return r.retries.Load()
+}
+
+// cacheResponse stores the response body of a resource returned with an ETag,
+// such that later requests can be made conditional using If-None-Match.
+// Expanded collections and pages of collections are not cached, since they are
+// large and change often, e.g. the pages of the event log. Responses are also
+// not cached once the cache of the host is full.
+func (r *Redfish) cacheResponse(path, etag string, body []byte) {
+	r.etags.Lock()
+	defer r.etags.Unlock()
+
+	old, ok := r.etags.entries[path]
+	if ok {
+		r.etags.size -= len(old.body)
+		delete(r.etags.entries, path)
+	}
+
+	if etag == "" || strings.Contains(path, "$expand=") || strings.Contains(path, "$skip") {
+		return
+	}
+
+	if r.etags.size+len(body) > maxEtagCacheSize {
+		return
+	}
+
+	r.etags.entries[path] = etagEntry{
+		etag: etag,
+		body: body,
+	}
+	r.etags.size += len(body)
+}
+
+// CacheStats returns the number of responses served from the ETag cache and
+// the number of responses that were downloaded in full
+func (r *Redfish) CacheStats() (uint64, uint64) {
+	return r.hits.Load(), r.misses.Load()
 }
 
 func (r *Redfish) Exists(path string) bool {
@@ -284,17 +631,13 @@ func (r *Redfish) Exists(path string) bool {
 	}
 
 	url := fmt.Sprintf("%s%s", r.baseurl, path)
-	req, err := http.NewRequest("HEAD", url, nil)
+	req, err := http.NewRequestWithContext(r.requestContext(), "HEAD", url, nil)
 	if err != nil {
 		return false
 	}
 
 	req.Header.Add("Accept", "application/json")
-	if len(r.session.token) > 0 {
-		req.Header.Set("X-Auth-Token", r.session.token)
-	} else {
-		req.SetBasicAuth(r.username, r.password)
-	}
+	r.authorize(req)
 
 	resp, err := r.http.Do(req)
 	if resp != nil {
@@ -310,3 +653,330 @@ func (r *Redfish) Exists(path string) bool {
 
 	return true
 }
+
+// SetExpand selects the $expand query used when fetching collections, based on
+// the protocol features advertised in the service root. The members are only
+// expanded one level, such that they are returned inline with the collection.
+func (r *Redfish) SetExpand(root *V1Response) {
+	expand := root.ProtocolFeaturesSupported.ExpandQuery
+
+	if expand.NoLinks {
+		r.expand = "$expand=."
+	} else if expand.ExpandAll {
+		r.expand = "$expand=*"
+	} else {
+		r.expand = ""
+		return
+	}
+
+	if expand.Levels && expand.MaxLevels > 0 {
+		r.expand += "($levels=1)"
+	}
+
+	log.Debug("Using expand query %q for %s", r.expand, r.hostname)
+}
+
+// SetSelect enables the $select query parameter when it is enabled in the
+// configuration and supported by the service.
+func (r *Redfish) SetSelect(root *V1Response) {
+	r.selects = config.GetUseSelect() && root.ProtocolFeaturesSupported.SelectQuery
+	if r.selects {
+		log.Debug("Using select query for %s", r.hostname)
+	}
+}
+
+// SelectEnabled reports whether the $select query parameter is in effect
+func (r *Redfish) SelectEnabled() bool {
+	return r.selects && !r.noSelect.Load()
+}
+
+// GetSelect fetches the resource at the given path, but only requests the
+// listed properties when the $select query parameter is in effect.
+func (r *Redfish) GetSelect(path string, props []string, res any) bool {
+	if len(props) == 0 || !r.SelectEnabled() {
+		return r.Get(path, res)
+	}
+	query := "$select=" + strings.Join(props, ",")
+	return r.getWithFallback(path, query, &r.noSelect, "Select", res)
+}
+
+// GetCollection fetches all pages of the collection at the given path, with
+// the members expanded when the service supports it.
+func (r *Redfish) GetCollection(path string, res *CollectionResponse) bool {
+	if r.expand == "" || r.noExpand.Load() {
+		return r.GetPages(path, res)
+	}
+
+	ok := r.getWithFallback(path, r.expand, &r.noExpand, "Expand", res)
+	if !ok {
+		return false
+	}
+
+	// Some services accept the query, but ignore it
+	if len(res.Members) > 0 && !slices.ContainsFunc(res.Members, isExpanded) {
+		r.disableQuery(&r.noExpand, "Expand", "members are not expanded")
+	}
+
+	if !r.noExpand.Load() {
+		path = withQuery(path, r.expand)
+	}
+
+	return r.getNextPages(path, res)
+}
+
+// GetPages fetches all pages of the collection at the given path
+func (r *Redfish) GetPages(path string, res *CollectionResponse) bool {
+	ok := r.Get(path, res)
+	if !ok {
+		return false
+	}
+	return r.getNextPages(path, res)
+}
+
+// GetGroup fetches all pages of the collection at the given path, but only
+// decodes the links to the members
+func (r *Redfish) GetGroup(path string, res *GroupResponse) bool {
+	coll := CollectionResponse{}
+	ok := r.GetPages(path, &coll)
+	if !ok {
+		return false
+	}
+
+	res.Name = coll.Name
+	res.Description = coll.Description
+	res.Members = make(OdataSlice, len(coll.Members))
+
+	for n, raw := range coll.Members {
+		err := json.Unmarshal(raw, &res.Members[n])
+		if err != nil {
+			log.Error("Error decoding member of %q: %v", path, err)
+			return false
+		}
+	}
+
+	return true
+}
+
+// getNextPages follows the pagination of a collection, where the first page is
+// already stored in res. The members of the following pages are appended to
+// res. Pages are followed using Members@odata.nextLink, or using $skip when the
+// reported member count exceeds the number of members returned. At most
+// max_pages pages are fetched, after which the collection is truncated.
+func (r *Redfish) getNextPages(path string, res *CollectionResponse) bool {
+	seen := map[string]bool{}
+	for _, raw := range res.Members {
+		seen[memberId(raw)] = true
+	}
+
+	for page := 1; ; page++ {
+		next := res.NextLink
+		if next == "" && res.Count > len(res.Members) {
+			next = withQuery(path, fmt.Sprintf("$skip=%d", len(res.Members)))
+		}
+
+		if next == "" {
+			return true
+		}
+
+		if page >= int(config.GetMaxPages()) {
+			r.truncated.Add(1)
+			log.Warn("Collection %q on %s truncated after %d pages", path, r.hostname, page)
+			return true
+		}
+
+		if u, err := neturl.Parse(next); err == nil && u.IsAbs() {
+			next = u.RequestURI()
+		}
+
+		resp := CollectionResponse{}
+		ok := r.Get(next, &resp)
+		if !ok {
+			return false
+		}
+
+		// Stop when nothing new is returned, e.g. when $skip is ignored
+		added := 0
+		for _, raw := range resp.Members {
+			id := memberId(raw)
+			if id != "" && seen[id] {
+				continue
+			}
+			seen[id] = true
+			res.Members = append(res.Members, raw)
+			added++
+		}
+
+		if added == 0 {
+			return true
+		}
+
+		res.NextLink = resp.NextLink
+		if resp.Count > 0 {
+			res.Count = resp.Count
+		}
+	}
+}
+
+// Truncated returns the number of collections that have been truncated
+// because the page limit was reached
+func (r *Redfish) Truncated() uint64 {
+	return r.truncated.Load()
+}
+
+// getWithFallback fetches the given path with the query appended. If the
+// service rejects the query as unsupported, the regular request is made and
+// the query is disabled for the host. Other failures, such as timeouts, are
+// returned as they are.
+func (r *Redfish) getWithFallback(path, query string, disabled *atomic.Bool, name string, res any) bool {
+	err := r.get(withQuery(path, query), res)
+	if err == nil {
+		return true
+	}
+
+	var se *statusError
+	if !errors.As(err, &se) || (se.code != http.StatusBadRequest && se.code != http.StatusNotImplemented) {
+		return false
+	}
+
+	if !r.Get(path, res) {
+		return false
+	}
+
+	r.disableQuery(disabled, name, se.status)
+	return true
+}
+
+// disableQuery disables a query parameter for the host, since the service
+// does not support it
+func (r *Redfish) disableQuery(disabled *atomic.Bool, name, reason string) {
+	if !disabled.Swap(true) {
+		log.Info("%s query disabled for %s: %s", name, r.hostname, reason)
+	}
+}
+
+// Member is a single member of a collection together with its link
+type Member[T any] struct {
+	Link string
+	Data T
+}
+
+// GetMembers fetches the collection at the given path and decodes all of its
+// members. Members that are expanded inline are decoded directly, while the
+// remaining members (e.g. when the service does not support $expand or only
+// returns a partial expansion) are fetched one by one, requesting only the
+// listed properties when $select is in effect.
+func GetMembers[T any](r *Redfish, path string, props []string) ([]Member[T], bool) {
+	coll := CollectionResponse{}
+	ok := r.GetCollection(path, &coll)
+	if !ok {
+		return nil, false
+	}
+
+	list := []Member[T]{}
+	seen := map[string]bool{}
+	fetch := []int{}
+
+	for _, raw := range coll.Members {
+		link := Odata{}
+		err := json.Unmarshal(raw, &link)
+		if err != nil {
+			log.Error("Error decoding member of %q: %v", path, err)
+			return nil, false
+		}
+
+		if link.OdataId == "" || seen[link.OdataId] {
+			continue
+		}
+		seen[link.OdataId] = true
+
+		m := Member[T]{Link: link.OdataId}
+		if isExpanded(raw) {
+			err = json.Unmarshal(raw, &m.Data)
+			if err != nil {
+				log.Error("Error decoding member %q: %v", m.Link, err)
+				return nil, false
+			}
+		} else {
+			fetch = append(fetch, len(list))
+		}
+
+		list = append(list, m)
+	}
+
+	ok = parallel(len(fetch), func(n int) bool {
+		m := &list[fetch[n]]
+		return r.GetSelect(m.Link, props, &m.Data)
+	})
+	if !ok {
+		return nil, false
+	}
+
+	return list, true
+}
+
+// GetResources fetches the resources at the given links concurrently, while
+// keeping the order of the links. The number of simultaneous requests is
+// bounded by the concurrency setting.
+func GetResources[T any](r *Redfish, links []string, props []string) ([]T, bool) {
+	list := make([]T, len(links))
+	ok := parallel(len(links), func(n int) bool {
+		return r.GetSelect(links[n], props, &list[n])
+	})
+	if !ok {
+		return nil, false
+	}
+	return list, true
+}
+
+// parallel calls fn concurrently for all indices from 0 to n-1 and reports
+// whether all of the calls were successful
+func parallel(n int, fn func(n int) bool) bool {
+	var wg sync.WaitGroup
+	var failed atomic.Bool
+
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			if !fn(i) {
+				failed.Store(true)
+			}
+			wg.Done()
+		}()
+	}
+
+	wg.Wait()
+	return !failed.Load()
+}
+
+// memberId returns the link of a collection member
+func memberId(raw json.RawMessage) string {
+	link := Odata{}
+	json.Unmarshal(raw, &link)
+	return link.OdataId
+}
+
+// isExpanded reports whether a collection member contains more than just the
+// OData annotations, i.e. whether the member was expanded by the service.
+func isExpanded(raw json.RawMessage) bool {
+	var m map[string]json.RawMessage
+
+	err := json.Unmarshal(raw, &m)
+	if err != nil {
+		return false
+	}
+
+	for k := range m {
+		if !strings.HasPrefix(k, "@odata.") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func withQuery(path, query string) string {
+	if strings.Contains(path, "?") {
+		return path + "&" + query
+	}
+	return path + "?" + query
+}
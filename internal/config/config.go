@@ -1,9 +1,14 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/mrlhansen/idrac_exporter/internal/log"
 	"github.com/xhit/go-str2duration/v2"
@@ -13,17 +18,36 @@ import (
 var Debug bool = false
 var Config *RootConfig = nil
 
+var ErrMissingCredentials = errors.New("missing credentials")
+
 func (c *AuthConfig) Validate() error {
 	if c == nil {
 		return fmt.Errorf("empty section")
 	}
 
-	if c.Username == "" {
-		return fmt.Errorf("missing username")
+	err := c.TLS.Validate()
+	if err != nil {
+		return err
 	}
 
-	if c.Password == "" {
-		return fmt.Errorf("missing password")
+	for k := range c.Metrics {
+		if k != "all" && new(CollectConfig).Group(k) == nil {
+			return fmt.Errorf("invalid metrics group: %s", k)
+		}
+	}
+
+	if c.UsernameFile != "" {
+		c.Username, err = readSecretFile(c.UsernameFile)
+		if err != nil {
+			return fmt.Errorf("read username_file: %v", err)
+		}
+	}
+
+	if c.PasswordFile != "" {
+		c.Password, err = readSecretFile(c.PasswordFile)
+		if err != nil {
+			return fmt.Errorf("read password_file: %v", err)
+		}
 	}
 
 	switch c.Scheme {
@@ -34,15 +58,318 @@ func (c *AuthConfig) Validate() error {
 		return fmt.Errorf("invalid scheme")
 	}
 
+	// Login credentials are not needed with client certificate authentication
+	if c.CertAuth() {
+		if c.Scheme != "https" {
+			return fmt.Errorf("client certificates require scheme https")
+		}
+		return nil
+	}
+
+	if c.Username == "" {
+		return fmt.Errorf("%w: username", ErrMissingCredentials)
+	}
+
+	if c.Password == "" {
+		return fmt.Errorf("%w: password", ErrMissingCredentials)
+	}
+
+	return nil
+}
+
+// Credentials returns the login credentials for the given target. Files are
+// read again on every call, and credentials found by the secret provider take
+// precedence over the credentials from the configuration file.
+func (c *AuthConfig) Credentials(target string) (string, string, error) {
+	var err error
+	username := c.Username
+	password := c.Password
+
+	if c.UsernameFile != "" {
+		username, err = readSecretFile(c.UsernameFile)
+		if err != nil {
+			return "", "", fmt.Errorf("read username_file: %v", err)
+		}
+	}
+
+	if c.PasswordFile != "" {
+		password, err = readSecretFile(c.PasswordFile)
+		if err != nil {
+			return "", "", fmt.Errorf("read password_file: %v", err)
+		}
+	}
+
+	if p := GetSecretProvider(); p != nil {
+		u, pw, ok, err := p.GetCredentials(target)
+		if err != nil {
+			return "", "", fmt.Errorf("secret provider: %v", err)
+		}
+		if ok {
+			if u != "" {
+				username = u
+			}
+			password = pw
+		}
+	}
+
+	if username == "" {
+		return "", "", fmt.Errorf("%w: username", ErrMissingCredentials)
+	}
+
+	if password == "" {
+		return "", "", fmt.Errorf("%w: password", ErrMissingCredentials)
+	}
+
+	return username, password, nil
+}
+
+// CertAuth reports whether a client certificate is used for authentication
+func (c *AuthConfig) CertAuth() bool {
+	return c.TLS.ClientCert != nil
+}
+
+// Equal reports whether two hosts have the same effective settings for
+// connecting to the Redfish API. The metrics groups are not compared, since
+// they are applied on every scrape.
+func (c *AuthConfig) Equal(o *AuthConfig) bool {
+	return c.Username == o.Username &&
+		c.Password == o.Password &&
+		c.UsernameFile == o.UsernameFile &&
+		c.PasswordFile == o.PasswordFile &&
+		c.Scheme == o.Scheme &&
+		c.Port == o.Port &&
+		c.BasicAuth == o.BasicAuth &&
+		c.Timeout == o.Timeout &&
+		c.Concurrency == o.Concurrency &&
+		c.Event.SeverityLevel == o.Event.SeverityLevel &&
+		c.Event.MaxAgeSeconds == o.Event.MaxAgeSeconds &&
+		c.TLS.Equal(&o.TLS)
+}
+
+func (c *ClientTLSConfig) Equal(o *ClientTLSConfig) bool {
+	return c.CAFile == o.CAFile &&
+		c.ServerName == o.ServerName &&
+		c.InsecureSkipVerify() == o.InsecureSkipVerify() &&
+		c.Version == o.Version &&
+		c.CertFile == o.CertFile &&
+		c.KeyFile == o.KeyFile &&
+		c.RootCAs.Equal(o.RootCAs)
+}
+
+func (c *ClientTLSConfig) Validate() error {
+	switch c.MinVersion {
+	case "":
+		c.Version = 0
+	case "1.0":
+		c.Version = tls.VersionTLS10
+	case "1.1":
+		c.Version = tls.VersionTLS11
+	case "1.2":
+		c.Version = tls.VersionTLS12
+	case "1.3":
+		c.Version = tls.VersionTLS13
+	default:
+		return fmt.Errorf("invalid tls min_version: %s", c.MinVersion)
+	}
+
+	c.ClientCert = nil
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return fmt.Errorf("load tls client certificate: %v", err)
+		}
+		c.ClientCert = &cert
+	}
+
+	c.RootCAs = nil
+	if c.CAFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.CAFile)
+	if err != nil {
+		return fmt.Errorf("read tls ca_file: %v", err)
+	}
+
+	c.RootCAs = x509.NewCertPool()
+	if !c.RootCAs.AppendCertsFromPEM(data) {
+		return fmt.Errorf("no certificates found in tls ca_file: %s", c.CAFile)
+	}
+
+	return nil
+}
+
+// InsecureSkipVerify reports whether the certificate of the host should be
+// verified. Unless explicitly configured, certificates are only verified when
+// a CA file is given.
+func (c *ClientTLSConfig) InsecureSkipVerify() bool {
+	if c.Insecure != nil {
+		return *c.Insecure
+	}
+	return c.CAFile == ""
+}
+
+// Group returns the setting for the metrics group with the given name, or nil
+// when there is no such group
+func (c *CollectConfig) Group(name string) *bool {
+	switch name {
+	case "system":
+		return &c.System
+	case "sensors":
+		return &c.Sensors
+	case "events":
+		return &c.Events
+	case "power":
+		return &c.Power
+	case "storage":
+		return &c.Storage
+	case "memory":
+		return &c.Memory
+	case "network":
+		return &c.Network
+	case "processors":
+		return &c.Processors
+	case "manager":
+		return &c.Manager
+	case "extra":
+		return &c.Extra
+	}
+	return nil
+}
+
+// SetAll enables all metrics groups
+func (c *CollectConfig) SetAll() {
+	c.All = true
+	c.System = true
+	c.Sensors = true
+	c.Events = true
+	c.Power = true
+	c.Storage = true
+	c.Memory = true
+	c.Network = true
+	c.Processors = true
+	c.Manager = true
+	c.Extra = true
+}
+
+// GetCollectConfig returns the metrics groups to collect for the target. The
+// global settings are overridden by the metrics section of the host (or auth
+// group), and groups is an optional comma-separated list of metrics groups
+// that replaces the configured groups.
+func GetCollectConfig(target, auth, groups string) (CollectConfig, error) {
+	Config.Mutex.RLock()
+	defer Config.Mutex.RUnlock()
+
+	collect := Config.Collect
+
+	if groups != "" {
+		collect = CollectConfig{}
+		for _, name := range strings.Split(groups, ",") {
+			name = strings.TrimSpace(name)
+			if name == "all" {
+				collect.SetAll()
+				continue
+			}
+			g := collect.Group(name)
+			if g == nil {
+				return collect, fmt.Errorf("invalid metrics group: %s", name)
+			}
+			*g = true
+		}
+		return collect, nil
+	}
+
+	var host *AuthConfig
+	if len(auth) > 0 {
+		host = Config.Auths[auth]
+	} else {
+		host, _ = matchHost(Config.Hosts, target)
+	}
+
+	if host == nil {
+		return collect, nil
+	}
+
+	if host.Metrics["all"] {
+		collect.SetAll()
+	}
+
+	for k, v := range host.Metrics {
+		if k != "all" {
+			*collect.Group(k) = v
+		}
+	}
+
+	return collect, nil
+}
+
+func (c *EventConfig) Validate() error {
+	switch strings.ToLower(c.Severity) {
+	case "ok":
+		c.SeverityLevel = 0
+	case "warning", "":
+		c.SeverityLevel = 1
+	case "critical":
+		c.SeverityLevel = 2
+	default:
+		return fmt.Errorf("invalid value: %s", c.Severity)
+	}
+
+	if c.MaxAge == "" {
+		c.MaxAge = "7d"
+	}
+
+	t, err := str2duration.ParseDuration(c.MaxAge)
+	if err != nil {
+		return fmt.Errorf("unable to parse duration: %v", err)
+	}
+	c.MaxAgeSeconds = t.Seconds()
+
 	return nil
 }
 
+// Validates a host or auth group, where settings that are not specified are
+// inherited from the root. Credentials may be left out when they are provided
+// by the secret provider.
+func (c *RootConfig) validateAuth(auth *AuthConfig) error {
+	if auth == nil {
+		return fmt.Errorf("empty section")
+	}
+
+	if auth.Timeout == 0 {
+		auth.Timeout = c.Timeout
+	}
+
+	if auth.Concurrency == 0 {
+		auth.Concurrency = c.Concurrency
+	}
+
+	if auth.Event.Severity == "" {
+		auth.Event.Severity = c.Event.Severity
+	}
+
+	if auth.Event.MaxAge == "" {
+		auth.Event.MaxAge = c.Event.MaxAge
+	}
+
+	err := auth.Event.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = auth.Validate()
+	if errors.Is(err, ErrMissingCredentials) && c.Secrets.Provider != nil {
+		return nil
+	}
+	return err
+}
+
 func GetAuthConfig(target, auth string) *AuthConfig {
 	var host *AuthConfig
 	var ok bool
 
-	Config.Mutex.Lock()
-	defer Config.Mutex.Unlock()
+	Config.Mutex.RLock()
+	defer Config.Mutex.RUnlock()
 
 	if len(auth) > 0 {
 		host, ok = Config.Auths[auth]
@@ -53,19 +380,108 @@ func GetAuthConfig(target, auth string) *AuthConfig {
 		return host
 	}
 
-	host, ok = Config.Hosts[target]
-	if ok {
-		return host
-	}
-
-	host, ok = Config.Hosts["default"]
-	if !ok {
+	host, m := matchHost(Config.Hosts, target)
+	if host == nil {
 		log.Error("Could not find login credentials: host=%s", target)
 		return nil
 	}
+
+	log.Debug("Host %s matched %s rule %q", target, m.Kind, m.Rule)
+	return host
+}
+
+// LookupAuthConfig is like GetAuthConfig, but without logging when there are no
+// login credentials for the target
+func LookupAuthConfig(target, auth string) *AuthConfig {
+	Config.Mutex.RLock()
+	defer Config.Mutex.RUnlock()
+
+	if len(auth) > 0 {
+		return Config.Auths[auth]
+	}
+
+	host, _ := matchHost(Config.Hosts, target)
 	return host
 }
 
+// DiffAuths compares two hosts or auths sections and returns the sorted names
+// of the entries that were added, removed or changed
+func DiffAuths(old, new map[string]*AuthConfig) (added, removed, changed []string) {
+	for k, v := range new {
+		o, ok := old[k]
+		if !ok {
+			added = append(added, k)
+		} else if !o.Equal(v) {
+			changed = append(changed, k)
+		}
+	}
+
+	for k := range old {
+		_, ok := new[k]
+		if !ok {
+			removed = append(removed, k)
+		}
+	}
+
+	slices.Sort(added)
+	slices.Sort(removed)
+	slices.Sort(changed)
+	return
+}
+
+// The settings below can be changed by reloading the configuration, and must
+// therefore be read with the mutex held
+
+func GetDefaultTarget() string {
+	Config.Mutex.RLock()
+	defer Config.Mutex.RUnlock()
+	return Config.DefaultTarget
+}
+
+// GetRefreshInterval returns the refresh interval of the metrics group, which
+// is zero when the group is read on every scrape
+func GetRefreshInterval(group string) time.Duration {
+	Config.Mutex.RLock()
+	defer Config.Mutex.RUnlock()
+	return Config.Refresh.Intervals[group]
+}
+
+func GetRetryConfig() RetryConfig {
+	Config.Mutex.RLock()
+	defer Config.Mutex.RUnlock()
+	return Config.Retry
+}
+
+func GetBreakerConfig() BreakerConfig {
+	Config.Mutex.RLock()
+	defer Config.Mutex.RUnlock()
+	return Config.Breaker
+}
+
+func GetCollectorsConfig() CollectorsConfig {
+	Config.Mutex.RLock()
+	defer Config.Mutex.RUnlock()
+	return Config.Collectors
+}
+
+func GetSecretProvider() SecretProvider {
+	Config.Mutex.RLock()
+	defer Config.Mutex.RUnlock()
+	return Config.Secrets.Provider
+}
+
+func GetUseSelect() bool {
+	Config.Mutex.RLock()
+	defer Config.Mutex.RUnlock()
+	return Config.UseSelect
+}
+
+func GetMaxPages() uint {
+	Config.Mutex.RLock()
+	defer Config.Mutex.RUnlock()
+	return Config.MaxPages
+}
+
 func NewConfig() *RootConfig {
 	return &RootConfig{
 		Hosts: make(map[string]*AuthConfig),
@@ -115,13 +531,31 @@ func (c *RootConfig) Validate() error {
 		c.Concurrency = 10
 	}
 
+	if c.MaxPages == 0 {
+		c.MaxPages = 10
+	}
+
 	if c.MetricsPrefix == "" {
 		c.MetricsPrefix = "idrac"
 	}
 
+	// secrets
+	c.Secrets.Provider = nil
+	if c.Secrets.Directory != "" {
+		p, err := NewDirectoryProvider(c.Secrets.Directory)
+		if err != nil {
+			return fmt.Errorf("secrets: %v", err)
+		}
+		c.Secrets.Provider = p
+	}
+
 	// hosts
 	for k, v := range c.Hosts {
-		err := v.Validate()
+		_, err := compilePattern(k)
+		if err != nil {
+			return fmt.Errorf("host=%s: %v", k, err)
+		}
+		err = c.validateAuth(v)
 		if err != nil {
 			return fmt.Errorf("host=%s: %v", k, err)
 		}
@@ -129,46 +563,114 @@ func (c *RootConfig) Validate() error {
 
 	// auths
 	for k, v := range c.Auths {
-		err := v.Validate()
+		err := c.validateAuth(v)
 		if err != nil {
 			return fmt.Errorf("auth=%s: %v", k, err)
 		}
 	}
 
 	// events
-	switch strings.ToLower(c.Event.Severity) {
-	case "ok":
-		c.Event.SeverityLevel = 0
-	case "warning", "":
-		c.Event.SeverityLevel = 1
-	case "critical":
-		c.Event.SeverityLevel = 2
-	default:
-		return fmt.Errorf("invalid value: %s", c.Event.Severity)
+	err := c.Event.Validate()
+	if err != nil {
+		return err
+	}
+
+	// polling
+	if c.Poll.Interval == "" {
+		c.Poll.Interval = "60s"
+	}
+
+	t, err := str2duration.ParseDuration(c.Poll.Interval)
+	if err != nil {
+		return fmt.Errorf("unable to parse duration: %v", err)
+	}
+	if t < time.Second {
+		return fmt.Errorf("polling interval must be at least 1s")
+	}
+	c.Poll.IntervalDuration = t
+
+	// retry
+	if c.Retry.MaxAttempts == 0 {
+		c.Retry.MaxAttempts = 1
 	}
 
-	if c.Event.MaxAge == "" {
-		c.Event.MaxAge = "7d"
+	if c.Retry.Backoff == "" {
+		c.Retry.Backoff = "1s"
 	}
 
-	t, err := str2duration.ParseDuration(c.Event.MaxAge)
+	if c.Retry.MaxBackoff == "" {
+		c.Retry.MaxBackoff = "10s"
+	}
+
+	if c.Retry.StatusCodes == nil {
+		c.Retry.StatusCodes = []int{429, 502, 503, 504}
+	}
+
+	t, err = str2duration.ParseDuration(c.Retry.Backoff)
+	if err != nil {
+		return fmt.Errorf("unable to parse duration: %v", err)
+	}
+	c.Retry.BackoffDuration = t
+
+	t, err = str2duration.ParseDuration(c.Retry.MaxBackoff)
 	if err != nil {
 		return fmt.Errorf("unable to parse duration: %v", err)
 	}
-	c.Event.MaxAgeSeconds = t.Seconds()
+	c.Retry.MaxBackoffDuration = t
+
+	if c.Retry.BackoffDuration <= 0 || c.Retry.MaxBackoffDuration < c.Retry.BackoffDuration {
+		return fmt.Errorf("invalid retry backoff: %s (max %s)", c.Retry.Backoff, c.Retry.MaxBackoff)
+	}
+
+	// circuit breaker
+	if c.Breaker.Cooldown == "" {
+		c.Breaker.Cooldown = "5m"
+	}
+
+	t, err = str2duration.ParseDuration(c.Breaker.Cooldown)
+	if err != nil {
+		return fmt.Errorf("unable to parse duration: %v", err)
+	}
+	c.Breaker.CooldownDuration = t
+
+	// collectors
+	if c.Collectors.IdleTimeout == "" {
+		c.Collectors.IdleTimeout = "1h"
+	}
+
+	t, err = str2duration.ParseDuration(c.Collectors.IdleTimeout)
+	if err != nil {
+		return fmt.Errorf("unable to parse duration: %v", err)
+	}
+	c.Collectors.IdleTimeoutDuration = t
+
+	// refresh
+	c.Refresh.Intervals = make(map[string]time.Duration)
+	for k, v := range map[string]string{
+		"system":     c.Refresh.System,
+		"sensors":    c.Refresh.Sensors,
+		"events":     c.Refresh.Events,
+		"power":      c.Refresh.Power,
+		"storage":    c.Refresh.Storage,
+		"memory":     c.Refresh.Memory,
+		"network":    c.Refresh.Network,
+		"processors": c.Refresh.Processors,
+		"manager":    c.Refresh.Manager,
+		"extra":      c.Refresh.Extra,
+	} {
+		if v == "" {
+			continue
+		}
+		t, err = str2duration.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("refresh=%s: unable to parse duration: %v", k, err)
+		}
+		c.Refresh.Intervals[k] = t
+	}
 
 	// metrics
 	if c.Collect.All {
-		c.Collect.System = true
-		c.Collect.Sensors = true
-		c.Collect.Events = true
-		c.Collect.Power = true
-		c.Collect.Storage = true
-		c.Collect.Memory = true
-		c.Collect.Network = true
-		c.Collect.Processors = true
-		c.Collect.Manager = true
-		c.Collect.Extra = true
+		c.Collect.SetAll()
 	}
 
 	return nil
@@ -13,14 +13,17 @@ type DiscoverItem struct {
 
 func GetDiscover() string {
 	var list []DiscoverItem
+
+	Config.Mutex.RLock()
 	for t := range Config.Hosts {
-		if t == "default" {
+		if t == "default" || IsPattern(t) {
 			continue
 		}
 		list = append(list, DiscoverItem{
 			Targets: []string{t},
 		})
 	}
+	Config.Mutex.RUnlock()
 
 	if len(list) == 0 {
 		return "[]"
@@ -48,6 +48,14 @@ func (c *RootConfig) FromEnvironment() {
 		password       string
 		scheme         string
 		port           uint
+		ca_file        string
+		server_name    string
+		min_version    string
+		insecure       string
+		cert_file      string
+		key_file       string
+		username_file  string
+		password_file  string
 	)
 
 	getEnvString("CONFIG_ADDRESS", &c.Address)
@@ -55,18 +63,48 @@ func (c *RootConfig) FromEnvironment() {
 	getEnvString("CONFIG_DEFAULT_TARGET", &c.DefaultTarget)
 	getEnvString("CONFIG_DEFAULT_USERNAME", &username)
 	getEnvString("CONFIG_DEFAULT_PASSWORD", &password)
+	getEnvString("CONFIG_DEFAULT_USERNAME_FILE", &username_file)
+	getEnvString("CONFIG_DEFAULT_PASSWORD_FILE", &password_file)
 	getEnvString("CONFIG_DEFAULT_SCHEME", &scheme)
+	getEnvString("CONFIG_SECRETS_DIRECTORY", &c.Secrets.Directory)
+	getEnvString("CONFIG_DEFAULT_TLS_CA_FILE", &ca_file)
+	getEnvString("CONFIG_DEFAULT_TLS_SERVER_NAME", &server_name)
+	getEnvString("CONFIG_DEFAULT_TLS_MIN_VERSION", &min_version)
+	getEnvString("CONFIG_DEFAULT_TLS_INSECURE_SKIP_VERIFY", &insecure)
+	getEnvString("CONFIG_DEFAULT_TLS_CERT_FILE", &cert_file)
+	getEnvString("CONFIG_DEFAULT_TLS_KEY_FILE", &key_file)
 	getEnvString("CONFIG_EVENTS_SEVERITY", &c.Event.Severity)
 	getEnvString("CONFIG_EVENTS_MAXAGE", &c.Event.MaxAge)
+	getEnvString("CONFIG_POLLING_INTERVAL", &c.Poll.Interval)
+	getEnvString("CONFIG_REFRESH_SYSTEM", &c.Refresh.System)
+	getEnvString("CONFIG_REFRESH_SENSORS", &c.Refresh.Sensors)
+	getEnvString("CONFIG_REFRESH_EVENTS", &c.Refresh.Events)
+	getEnvString("CONFIG_REFRESH_POWER", &c.Refresh.Power)
+	getEnvString("CONFIG_REFRESH_STORAGE", &c.Refresh.Storage)
+	getEnvString("CONFIG_REFRESH_MEMORY", &c.Refresh.Memory)
+	getEnvString("CONFIG_REFRESH_NETWORK", &c.Refresh.Network)
+	getEnvString("CONFIG_REFRESH_PROCESSORS", &c.Refresh.Processors)
+	getEnvString("CONFIG_REFRESH_MANAGER", &c.Refresh.Manager)
+	getEnvString("CONFIG_REFRESH_EXTRA", &c.Refresh.Extra)
+	getEnvString("CONFIG_RETRY_BACKOFF", &c.Retry.Backoff)
+	getEnvString("CONFIG_RETRY_MAX_BACKOFF", &c.Retry.MaxBackoff)
+	getEnvString("CONFIG_CIRCUIT_BREAKER_COOLDOWN", &c.Breaker.Cooldown)
+	getEnvString("CONFIG_COLLECTORS_IDLE_TIMEOUT", &c.Collectors.IdleTimeout)
 	getEnvString("CONFIG_TLS_CERT_FILE", &c.TLS.CertFile)
 	getEnvString("CONFIG_TLS_KEY_FILE", &c.TLS.KeyFile)
 
 	getEnvUint("CONFIG_PORT", &c.Port)
 	getEnvUint("CONFIG_TIMEOUT", &c.Timeout)
 	getEnvUint("CONFIG_CONCURRENCY", &c.Concurrency)
+	getEnvUint("CONFIG_MAX_PAGES", &c.MaxPages)
+	getEnvUint("CONFIG_RETRY_MAX_ATTEMPTS", &c.Retry.MaxAttempts)
+	getEnvUint("CONFIG_CIRCUIT_BREAKER_THRESHOLD", &c.Breaker.Threshold)
+	getEnvUint("CONFIG_COLLECTORS_MAX_SIZE", &c.Collectors.MaxSize)
 	getEnvUint("CONFIG_DEFAULT_PORT", &port)
 
 	getEnvBool("CONFIG_DEFAULT_USE_BASIC_AUTH", &use_basic_auth)
+	getEnvBool("CONFIG_USE_SELECT_QUERY", &c.UseSelect)
+	getEnvBool("CONFIG_POLLING_ENABLED", &c.Poll.Enabled)
 	getEnvBool("CONFIG_TLS_ENABLED", &c.TLS.Enabled)
 	getEnvBool("CONFIG_METRICS_ALL", &c.Collect.All)
 	getEnvBool("CONFIG_METRICS_SYSTEM", &c.Collect.System)
@@ -95,6 +133,16 @@ func (c *RootConfig) FromEnvironment() {
 		ok = true
 	}
 
+	if len(username_file) > 0 {
+		def.UsernameFile = username_file
+		ok = true
+	}
+
+	if len(password_file) > 0 {
+		def.PasswordFile = password_file
+		ok = true
+	}
+
 	if len(scheme) > 0 {
 		def.Scheme = scheme
 		ok = true
@@ -110,6 +158,38 @@ func (c *RootConfig) FromEnvironment() {
 		ok = true
 	}
 
+	if len(ca_file) > 0 {
+		def.TLS.CAFile = ca_file
+		ok = true
+	}
+
+	if len(server_name) > 0 {
+		def.TLS.ServerName = server_name
+		ok = true
+	}
+
+	if len(min_version) > 0 {
+		def.TLS.MinVersion = min_version
+		ok = true
+	}
+
+	if len(cert_file) > 0 {
+		def.TLS.CertFile = cert_file
+		ok = true
+	}
+
+	if len(key_file) > 0 {
+		def.TLS.KeyFile = key_file
+		ok = true
+	}
+
+	if len(insecure) > 0 {
+		skip := false
+		getEnvBool("CONFIG_DEFAULT_TLS_INSECURE_SKIP_VERIFY", &skip)
+		def.TLS.Insecure = &skip
+		ok = true
+	}
+
 	if ok {
 		c.Hosts["default"] = def
 	}
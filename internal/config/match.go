@@ -0,0 +1,193 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Kinds of entries in the hosts section, in order of precedence
+const (
+	MatchExact    = "exact"
+	MatchCIDR     = "cidr"
+	MatchWildcard = "wildcard"
+	MatchRegex    = "regex"
+	MatchDefault  = "default"
+)
+
+type hostPattern struct {
+	kind   string
+	prefix int // size of the network prefix or number of literal characters
+	ipnet  *net.IPNet
+	regex  *regexp.Regexp
+}
+
+// Compiled patterns, keyed by the entry in the hosts section
+var patterns sync.Map
+
+var matchRank = map[string]int{
+	MatchCIDR:     0,
+	MatchWildcard: 1,
+	MatchRegex:    2,
+}
+
+// HostMatch describes which entry in the hosts section a target resolved to
+type HostMatch struct {
+	Target string `json:"target"`
+	Rule   string `json:"rule,omitempty"`
+	Kind   string `json:"kind,omitempty"`
+}
+
+// IsPattern reports whether an entry in the hosts section is a pattern that
+// matches multiple targets, rather than the name of a single target. Patterns
+// are either networks in CIDR notation (10.20.0.0/16), wildcards using * and ?
+// (*.oob.example.com) or regular expressions prefixed by ~ (~bmc[0-9]+).
+// Wildcards and regular expressions are matched case-insensitively, since host
+// names are not case-sensitive, and must match the whole host name.
+func IsPattern(key string) bool {
+	kind := getPattern(key).kind
+	return kind != MatchExact && kind != MatchDefault
+}
+
+func compilePattern(key string) (*hostPattern, error) {
+	if key == "default" {
+		return &hostPattern{kind: MatchDefault}, nil
+	}
+
+	if expr, ok := strings.CutPrefix(key, "~"); ok {
+		re, err := regexp.Compile("(?i)^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %v", err)
+		}
+		return &hostPattern{kind: MatchRegex, regex: re}, nil
+	}
+
+	if strings.Contains(key, "/") {
+		_, ipnet, err := net.ParseCIDR(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network: %v", err)
+		}
+		ones, _ := ipnet.Mask.Size()
+		return &hostPattern{kind: MatchCIDR, prefix: ones, ipnet: ipnet}, nil
+	}
+
+	if strings.ContainsAny(key, "*?") {
+		_, err := path.Match(key, "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid wildcard: %v", err)
+		}
+		literal := len(key) - strings.Count(key, "*") - strings.Count(key, "?")
+		return &hostPattern{kind: MatchWildcard, prefix: literal}, nil
+	}
+
+	return &hostPattern{kind: MatchExact}, nil
+}
+
+func getPattern(key string) *hostPattern {
+	p, ok := patterns.Load(key)
+	if ok {
+		return p.(*hostPattern)
+	}
+
+	c, err := compilePattern(key)
+	if err != nil {
+		// Invalid patterns are rejected during validation
+		c = &hostPattern{kind: MatchExact}
+	}
+
+	patterns.Store(key, c)
+	return c
+}
+
+// Returns the host name of the target without the port
+func hostname(target string) string {
+	host, _, err := net.SplitHostPort(target)
+	if err != nil {
+		return strings.Trim(target, "[]")
+	}
+	return host
+}
+
+func (p *hostPattern) match(key, target string) bool {
+	host := hostname(target)
+	switch p.kind {
+	case MatchCIDR:
+		ip := net.ParseIP(host)
+		return ip != nil && p.ipnet.Contains(ip)
+	case MatchWildcard:
+		ok, _ := path.Match(strings.ToLower(key), strings.ToLower(host))
+		return ok
+	case MatchRegex:
+		return p.regex.MatchString(host)
+	}
+	return false
+}
+
+// Reports whether pattern a is more specific than pattern b. Networks with a
+// longer prefix and wildcards with more literal characters are more specific.
+// Ties are broken by comparing the entries, such that the result is stable.
+func moreSpecific(a *hostPattern, akey string, b *hostPattern, bkey string) bool {
+	if matchRank[a.kind] != matchRank[b.kind] {
+		return matchRank[a.kind] < matchRank[b.kind]
+	}
+	if a.prefix != b.prefix {
+		return a.prefix > b.prefix
+	}
+	return akey < bkey
+}
+
+// Finds the entry in the hosts section for the target. Exact matches take
+// precedence over networks, which take precedence over wildcards and regular
+// expressions. The entry "default" is used when nothing else matches.
+func matchHost(hosts map[string]*AuthConfig, target string) (*AuthConfig, HostMatch) {
+	m := HostMatch{Target: target}
+
+	host, ok := hosts[target]
+	if ok && target != "default" {
+		m.Rule = target
+		m.Kind = MatchExact
+		return host, m
+	}
+
+	var best *hostPattern
+	for k, v := range hosts {
+		p := getPattern(k)
+		if p.kind == MatchExact || p.kind == MatchDefault {
+			continue
+		}
+		if !p.match(k, target) {
+			continue
+		}
+		if best == nil || moreSpecific(p, k, best, m.Rule) {
+			best = p
+			host = v
+			m.Rule = k
+			m.Kind = p.kind
+		}
+	}
+
+	if best != nil {
+		return host, m
+	}
+
+	host, ok = hosts["default"]
+	if ok {
+		m.Rule = "default"
+		m.Kind = MatchDefault
+		return host, m
+	}
+
+	return nil, m
+}
+
+// MatchHost returns which entry in the hosts section the target resolves to
+func MatchHost(target string) HostMatch {
+	Config.Mutex.RLock()
+	defer Config.Mutex.RUnlock()
+
+	_, m := matchHost(Config.Hosts, target)
+	return m
+}
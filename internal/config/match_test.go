@@ -0,0 +1,53 @@
+package config
+
+import "testing"
+
+func TestMatchHost(t *testing.T) {
+	hosts := map[string]*AuthConfig{}
+	for _, k := range []string{
+		"default",
+		"10.0.0.1",
+		"10.0.0.0/8",
+		"10.1.0.0/16",
+		"*.oob.example.com",
+		"bmc?.oob.example.com",
+		`~bmc[0-9]+\.oob\.example\.com`,
+		`~10\.0\.0\.1`,
+		`~host[0-9]+`,
+	} {
+		hosts[k] = &AuthConfig{}
+	}
+
+	tests := []struct {
+		target string
+		rule   string
+		kind   string
+	}{
+		{"10.0.0.1", "10.0.0.1", MatchExact},
+		{"10.0.0.2", "10.0.0.0/8", MatchCIDR},
+		{"10.1.2.3", "10.1.0.0/16", MatchCIDR},
+		{"10.1.2.3:443", "10.1.0.0/16", MatchCIDR},
+		{"110.0.0.12", "default", MatchDefault},
+		{"bmc1.oob.example.com", "bmc?.oob.example.com", MatchWildcard},
+		{"BMC1.OOB.example.com", "bmc?.oob.example.com", MatchWildcard},
+		{"bmc12.oob.example.com", "*.oob.example.com", MatchWildcard},
+		{"bmc12.oob.example.com:443", "*.oob.example.com", MatchWildcard},
+		{"host12", "~host[0-9]+", MatchRegex},
+		{"Host12:8443", "~host[0-9]+", MatchRegex},
+		{"host12.example.com", "default", MatchDefault},
+		{"other.example.com", "default", MatchDefault},
+	}
+
+	for _, tt := range tests {
+		host, m := matchHost(hosts, tt.target)
+		if host == nil || m.Rule != tt.rule || m.Kind != tt.kind {
+			t.Errorf("matchHost(%q) = %q (%s), want %q (%s)", tt.target, m.Rule, m.Kind, tt.rule, tt.kind)
+		}
+	}
+
+	delete(hosts, "default")
+	host, m := matchHost(hosts, "other.example.com")
+	if host != nil || m.Rule != "" {
+		t.Errorf("matchHost(%q) = %q, want no match", "other.example.com", m.Rule)
+	}
+}
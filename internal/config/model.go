@@ -1,13 +1,37 @@
 package config
 
-import "sync"
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"sync"
+	"time"
+)
 
 type AuthConfig struct {
-	Username  string `yaml:"username"`
-	Password  string `yaml:"password"`
-	Scheme    string `yaml:"scheme"`
-	Port      uint   `yaml:"port"`
-	BasicAuth bool   `yaml:"use_basic_auth"`
+	Username     string          `yaml:"username"`
+	Password     string          `yaml:"password"`
+	UsernameFile string          `yaml:"username_file"`
+	PasswordFile string          `yaml:"password_file"`
+	Scheme       string          `yaml:"scheme"`
+	Port         uint            `yaml:"port"`
+	BasicAuth    bool            `yaml:"use_basic_auth"`
+	TLS          ClientTLSConfig `yaml:"tls"`
+	Metrics      map[string]bool `yaml:"metrics"`
+	Timeout      uint            `yaml:"timeout"`
+	Concurrency  uint            `yaml:"concurrency"`
+	Event        EventConfig     `yaml:"events"`
+}
+
+type ClientTLSConfig struct {
+	CAFile     string `yaml:"ca_file"`
+	ServerName string `yaml:"server_name"`
+	Insecure   *bool  `yaml:"insecure_skip_verify"`
+	MinVersion string `yaml:"min_version"`
+	CertFile   string `yaml:"cert_file"`
+	KeyFile    string `yaml:"key_file"`
+	RootCAs    *x509.CertPool
+	Version    uint16
+	ClientCert *tls.Certificate
 }
 
 type CollectConfig struct {
@@ -24,6 +48,20 @@ type CollectConfig struct {
 	Extra      bool `yaml:"extra"`
 }
 
+type RefreshConfig struct {
+	System     string `yaml:"system"`
+	Sensors    string `yaml:"sensors"`
+	Events     string `yaml:"events"`
+	Power      string `yaml:"power"`
+	Storage    string `yaml:"storage"`
+	Memory     string `yaml:"memory"`
+	Network    string `yaml:"network"`
+	Processors string `yaml:"processors"`
+	Manager    string `yaml:"manager"`
+	Extra      string `yaml:"extra"`
+	Intervals  map[string]time.Duration
+}
+
 type EventConfig struct {
 	Severity      string `yaml:"severity"`
 	MaxAge        string `yaml:"maxage"`
@@ -31,6 +69,38 @@ type EventConfig struct {
 	MaxAgeSeconds float64
 }
 
+type PollConfig struct {
+	Enabled          bool   `yaml:"enabled"`
+	Interval         string `yaml:"interval"`
+	IntervalDuration time.Duration
+}
+
+type RetryConfig struct {
+	MaxAttempts        uint   `yaml:"max_attempts"`
+	Backoff            string `yaml:"backoff"`
+	MaxBackoff         string `yaml:"max_backoff"`
+	StatusCodes        []int  `yaml:"status_codes"`
+	BackoffDuration    time.Duration
+	MaxBackoffDuration time.Duration
+}
+
+type BreakerConfig struct {
+	Threshold        uint   `yaml:"threshold"`
+	Cooldown         string `yaml:"cooldown"`
+	CooldownDuration time.Duration
+}
+
+type CollectorsConfig struct {
+	IdleTimeout         string `yaml:"idle_timeout"`
+	MaxSize             uint   `yaml:"max_size"`
+	IdleTimeoutDuration time.Duration
+}
+
+type SecretsConfig struct {
+	Directory string `yaml:"directory"`
+	Provider  SecretProvider
+}
+
 type TLSConfig struct {
 	Enabled  bool   `yaml:"enabled"`
 	CertFile string `yaml:"cert_file"`
@@ -38,17 +108,25 @@ type TLSConfig struct {
 }
 
 type RootConfig struct {
-	Mutex         sync.Mutex
+	Mutex         sync.RWMutex
 	Address       string                 `yaml:"address"`
 	Port          uint                   `yaml:"port"`
 	HttpsProxy    string                 `yaml:"https_proxy"`
 	MetricsPrefix string                 `yaml:"metrics_prefix"`
 	DefaultTarget string                 `yaml:"default_target"`
 	Collect       CollectConfig          `yaml:"metrics"`
+	Refresh       RefreshConfig          `yaml:"refresh"`
 	Event         EventConfig            `yaml:"events"`
+	Poll          PollConfig             `yaml:"polling"`
+	Retry         RetryConfig            `yaml:"retry"`
+	Breaker       BreakerConfig          `yaml:"circuit_breaker"`
+	Secrets       SecretsConfig          `yaml:"secrets"`
+	Collectors    CollectorsConfig       `yaml:"collectors"`
 	TLS           TLSConfig              `yaml:"tls"`
 	Timeout       uint                   `yaml:"timeout"`
 	Concurrency   uint                   `yaml:"concurrency"`
+	UseSelect     bool                   `yaml:"use_select_query"`
+	MaxPages      uint                   `yaml:"max_pages"`
 	Hosts         map[string]*AuthConfig `yaml:"hosts"`
 	Auths         map[string]*AuthConfig `yaml:"auths"`
 }
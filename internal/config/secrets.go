@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SecretProvider looks up login credentials for a target in an external
+// source. The returned username is empty when the source only holds the
+// password, and ok is false when the source has no credentials for the target.
+type SecretProvider interface {
+	GetCredentials(target string) (username, password string, ok bool, err error)
+}
+
+// DirectoryProvider reads credentials from a directory with one file per host,
+// as with Kubernetes secrets mounted as a volume. The file either contains the
+// password, or the username and the password on two separate lines.
+type DirectoryProvider struct {
+	Path string
+}
+
+func NewDirectoryProvider(path string) (*DirectoryProvider, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", path)
+	}
+	return &DirectoryProvider{Path: path}, nil
+}
+
+func (p *DirectoryProvider) GetCredentials(target string) (string, string, bool, error) {
+	if target == "" || target != filepath.Base(target) || strings.HasPrefix(target, ".") {
+		return "", "", false, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(p.Path, target))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", "", false, nil
+	}
+	if err != nil {
+		return "", "", false, err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\r\n"), "\n")
+	if len(lines) >= 2 {
+		username := strings.TrimRight(lines[0], "\r")
+		password := strings.TrimRight(lines[1], "\r")
+		return username, password, true, nil
+	}
+
+	return "", lines[0], true, nil
+}
+
+func readSecretFile(filename string) (string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}